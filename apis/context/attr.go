@@ -0,0 +1,358 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"dirpx.dev/dlog/apis/field/fields"
+)
+
+// Key is a typed handle for a custom Pack attribute.
+//
+// Keys are created once, usually as package-level variables, with NewKey.
+// The name becomes the field key used by Pack.Fields and the JSON property
+// name used by Pack.MarshalJSON, so it should follow the canonical
+// lowercase, underscore-separated style of apis/field/fields.
+type Key[T any] struct {
+	info *keyInfo
+}
+
+// keyInfo is the type-erased description of a registered key.
+type keyInfo struct {
+	name   string
+	merge  func(old, new any) any
+	decode func(raw json.RawMessage) (any, error)
+	// accepts reports whether a value has the key's type.
+	accepts func(v any) bool
+}
+
+// attr is a single custom attribute value stored in a Pack.
+type attr struct {
+	key   *keyInfo
+	value any
+}
+
+// attrSet is an immutable, name-sorted set of custom attributes.
+// Packs share attrSets; every modification allocates a new one.
+type attrSet struct {
+	items []attr
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*keyInfo{}
+)
+
+// builtinNames are reserved: custom keys may not reuse them. They are the
+// JSON names of the fixed Pack attributes, which differ from the
+// canonical names for "instance" and "operation", and every canonical
+// field name of the base schema (the fixed attributes as seen by Lookup,
+// Set and Fields, and the record-level keys written by encoders and
+// plugins). The identity names (fields.TenantID, ...) are left free for
+// the well-known keys below.
+var builtinNames = map[string]struct{}{
+	// JSON names.
+	"instance": {}, "operation": {},
+
+	// Canonical names.
+	fields.SchemaVersion: {}, fields.Service: {}, fields.Version: {},
+	fields.Region: {}, fields.Env: {}, fields.NodeID: {},
+	fields.InstanceID: {}, fields.Timestamp: {}, fields.CorrelationID: {},
+	fields.TraceID: {}, fields.SpanID: {}, fields.Level: {},
+	fields.Component: {}, fields.Subsystem: {}, fields.Operation: {},
+	fields.Caller: {}, fields.Function: {}, fields.Stack: {},
+	fields.Error: {}, fields.ErrorType: {}, fields.ErrorCauses: {},
+	fields.ErrorStack: {}, fields.SampleRate: {}, fields.Suppressed: {},
+	fields.Fingerprint: {}, fields.RepeatCount: {}, fields.FirstSeen: {},
+	fields.LastSeen: {}, fields.Truncated: {}, fields.PipelineError: {},
+	fields.Message: {},
+}
+
+// NewKey registers a custom Pack attribute named name and returns its
+// typed handle.
+//
+// merge decides the result of Merge when both packs carry the attribute;
+// if nil, the later value replaces the earlier one, matching the rule for
+// the fixed string attributes.
+//
+// NewKey panics if name is empty, collides with a fixed Pack attribute or
+// a base schema field name (see apis/field/fields) or was already
+// registered, the same way flag and expvar treat duplicate
+// registrations: these are programming errors detected at init time.
+func NewKey[T any](name string, merge func(old, new T) T) Key[T] {
+	if name == "" {
+		panic("dlog: context key name is empty")
+	}
+	if _, ok := builtinNames[name]; ok {
+		panic(fmt.Sprintf("dlog: context key %q collides with a built-in attribute or field name", name))
+	}
+
+	info := &keyInfo{
+		name: name,
+		decode: func(raw json.RawMessage) (any, error) {
+			var v T
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		},
+		accepts: func(v any) bool {
+			_, ok := v.(T)
+			return ok
+		},
+	}
+	if merge != nil {
+		info.merge = func(old, new any) any {
+			return merge(old.(T), new.(T))
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("dlog: context key %q registered twice", name))
+	}
+	registry[name] = info
+	return Key[T]{info: info}
+}
+
+// lookupKey returns the registered key named name, or nil.
+func lookupKey(name string) *keyInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[name]
+}
+
+// Name returns the registered attribute name.
+func (k Key[T]) Name() string {
+	return k.info.name
+}
+
+// Get returns the value of the attribute in p and whether it is set.
+func (k Key[T]) Get(p Pack) (T, bool) {
+	var zero T
+	v, ok := p.ext.get(k.info.name)
+	if !ok {
+		return zero, false
+	}
+	return v.(T), true
+}
+
+// Set returns a copy of p with the attribute set to v.
+// p itself is not modified.
+func (k Key[T]) Set(p Pack, v T) Pack {
+	p.ext = p.ext.with(attr{key: k.info, value: v})
+	return p
+}
+
+// Delete returns a copy of p without the attribute.
+// p itself is not modified.
+func (k Key[T]) Delete(p Pack) Pack {
+	p.ext = p.ext.without(k.info.name)
+	return p
+}
+
+// Extractor returns an Extractor that yields a Pack holding only this
+// attribute, as produced by fn. If fn reports false, an empty Pack is
+// returned. Combine it with other extractors via Chain.
+func (k Key[T]) Extractor(fn func(ctx context.Context) (T, bool)) Extractor {
+	return ExtractorFunc(func(ctx context.Context) Pack {
+		v, ok := fn(ctx)
+		if !ok {
+			return Empty()
+		}
+		return k.Set(Empty(), v)
+	})
+}
+
+// Well-known custom attributes for multi-tenant services.
+// They are regular keys: the fixed Pack fields are unaffected.
+var (
+	// TenantID carries the tenant identifier (fields.TenantID).
+	TenantID = NewKey[string](fields.TenantID, nil)
+
+	// UserID carries the end-user identifier (fields.UserID).
+	UserID = NewKey[string](fields.UserID, nil)
+
+	// RequestID carries the inbound request identifier (fields.RequestID).
+	RequestID = NewKey[string](fields.RequestID, nil)
+
+	// Cohort carries the feature-flag cohort (fields.Cohort).
+	Cohort = NewKey[string](fields.Cohort, nil)
+)
+
+// Attr returns the value of the custom attribute named name, if set.
+// It is the untyped counterpart of Key.Get for generic consumers such as
+// encoders and expression evaluators.
+func (p Pack) Attr(name string) (any, bool) {
+	return p.ext.get(name)
+}
+
+// RangeAttrs calls fn for every custom attribute in name order until fn
+// returns false.
+func (p Pack) RangeAttrs(fn func(name string, value any) bool) {
+	if p.ext == nil {
+		return
+	}
+	for _, a := range p.ext.items {
+		if !fn(a.key.name, a.value) {
+			return
+		}
+	}
+}
+
+// len returns the number of attributes; a nil set is empty.
+func (s *attrSet) len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.items)
+}
+
+// index returns the position of name, or where it would be inserted.
+func (s *attrSet) index(name string) (int, bool) {
+	i := sort.Search(len(s.items), func(i int) bool {
+		return s.items[i].key.name >= name
+	})
+	return i, i < len(s.items) && s.items[i].key.name == name
+}
+
+// get returns the value stored under name.
+func (s *attrSet) get(name string) (any, bool) {
+	if s == nil {
+		return nil, false
+	}
+	if i, ok := s.index(name); ok {
+		return s.items[i].value, true
+	}
+	return nil, false
+}
+
+// with returns a new set with a inserted or replaced.
+func (s *attrSet) with(a attr) *attrSet {
+	if s == nil {
+		return &attrSet{items: []attr{a}}
+	}
+	i, ok := s.index(a.key.name)
+	items := make([]attr, 0, len(s.items)+1)
+	items = append(items, s.items[:i]...)
+	items = append(items, a)
+	if ok {
+		i++
+	}
+	items = append(items, s.items[i:]...)
+	return &attrSet{items: items}
+}
+
+// without returns a new set without name, or s itself if name is absent.
+func (s *attrSet) without(name string) *attrSet {
+	if s == nil {
+		return nil
+	}
+	i, ok := s.index(name)
+	if !ok {
+		return s
+	}
+	if len(s.items) == 1 {
+		return nil
+	}
+	items := make([]attr, 0, len(s.items)-1)
+	items = append(items, s.items[:i]...)
+	items = append(items, s.items[i+1:]...)
+	return &attrSet{items: items}
+}
+
+// mergeAttrs overlays b onto a using each key's merge rule.
+func mergeAttrs(a, b *attrSet) *attrSet {
+	if b.len() == 0 {
+		return a
+	}
+	if a.len() == 0 {
+		return b
+	}
+	out := a
+	for _, bv := range b.items {
+		if old, ok := out.get(bv.key.name); ok && bv.key.merge != nil {
+			bv.value = bv.key.merge(old, bv.value)
+		}
+		out = out.with(bv)
+	}
+	return out
+}
+
+// MarshalJSON encodes the fixed attributes exactly as the struct tags
+// describe them and appends custom attributes as additional properties.
+func (p Pack) MarshalJSON() ([]byte, error) {
+	type plain Pack
+	b, err := json.Marshal(plain(p))
+	if err != nil || p.ext.len() == 0 {
+		return b, err
+	}
+	b = b[:len(b)-1] // drop closing brace
+	for _, a := range p.ext.items {
+		name, err := json.Marshal(a.key.name)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(a.value)
+		if err != nil {
+			return nil, fmt.Errorf("dlog: encode context attribute %q: %w", a.key.name, err)
+		}
+		b = append(b, ',')
+		b = append(b, name...)
+		b = append(b, ':')
+		b = append(b, val...)
+	}
+	return append(b, '}'), nil
+}
+
+// UnmarshalJSON decodes the fixed attributes and every property that
+// matches a registered custom key. Unknown properties are ignored.
+func (p *Pack) UnmarshalJSON(b []byte) error {
+	type plain Pack
+	var fixed plain
+	if err := json.Unmarshal(b, &fixed); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	out := Pack(fixed)
+	out.ext = nil
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for name, msg := range raw {
+		info, ok := registry[name]
+		if !ok {
+			continue
+		}
+		v, err := info.decode(msg)
+		if err != nil {
+			return fmt.Errorf("dlog: decode context attribute %q: %w", name, err)
+		}
+		out.ext = out.ext.with(attr{key: info, value: v})
+	}
+	*p = out
+	return nil
+}
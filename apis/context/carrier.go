@@ -0,0 +1,105 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"context"
+
+	"dirpx.dev/dlog/apis/field"
+)
+
+// packKey and fieldsKey are the context keys for values stored by
+// WithPack and WithFields.
+type (
+	packKey   struct{}
+	fieldsKey struct{}
+)
+
+// WithPack returns a copy of ctx that carries p.
+//
+// If ctx already carries a Pack, p is overlaid on top of it using Merge,
+// so request-scoped values (operation, correlation) can be added without
+// losing the ones set further up the call chain.
+func WithPack(ctx context.Context, p Pack) context.Context {
+	if prev, ok := ctx.Value(packKey{}).(Pack); ok {
+		p = Merge(prev, p)
+	}
+	return context.WithValue(ctx, packKey{}, p)
+}
+
+// PackFrom returns the Pack carried by ctx, or an empty Pack.
+func PackFrom(ctx context.Context) Pack {
+	if ctx == nil {
+		return Empty()
+	}
+	if p, ok := ctx.Value(packKey{}).(Pack); ok {
+		return p
+	}
+	return Empty()
+}
+
+// FromContext returns an Extractor that yields the Pack stored in the
+// context by WithPack. It is typically the last element of a Chain so that
+// request-scoped values override process-level ones.
+func FromContext() Extractor {
+	return ExtractorFunc(PackFrom)
+}
+
+// WithFields returns a copy of ctx that carries fs in addition to any
+// fields already bound to ctx. Loggers append these fields to every record
+// logged with the returned context, so fields accumulate along a call chain.
+func WithFields(ctx context.Context, fs ...field.Field) context.Context {
+	if len(fs) == 0 {
+		return ctx
+	}
+	prev := FieldsFrom(ctx)
+	out := make([]field.Field, 0, len(prev)+len(fs))
+	out = append(append(out, prev...), fs...)
+	return context.WithValue(ctx, fieldsKey{}, out)
+}
+
+// FieldsFrom returns the fields bound to ctx via WithFields.
+// Callers must treat the returned slice as read-only.
+func FieldsFrom(ctx context.Context) []field.Field {
+	if ctx == nil {
+		return nil
+	}
+	fs, _ := ctx.Value(fieldsKey{}).([]field.Field)
+	return fs
+}
+
+// Detach returns a new background context that carries the Pack and the
+// bound fields of ctx, but none of its deadlines, cancellation or other
+// values. Use it for work that outlives the request that started it.
+func Detach(ctx context.Context) context.Context {
+	out := context.Background()
+	if p, ok := ctx.Value(packKey{}).(Pack); ok {
+		out = context.WithValue(out, packKey{}, p)
+	}
+	if fs := FieldsFrom(ctx); len(fs) > 0 {
+		out = context.WithValue(out, fieldsKey{}, fs)
+	}
+	return out
+}
+
+// Go runs fn in a new goroutine with a context detached from ctx (see
+// Detach), so logs written by background work stay correlated with the
+// request that spawned it.
+func Go(ctx context.Context, fn func(ctx context.Context)) {
+	dctx := Detach(ctx)
+	go fn(dctx)
+}
@@ -26,7 +26,13 @@
 //     context attributes the logger may want to inject into a record;
 //  2. Extractor   — an interface for pulling those attributes out of
 //     context.Context (for example, from OpenTelemetry, HTTP headers,
-//     or your own middleware).
+//     or your own middleware);
+//  3. WithPack / PackFrom and WithFields / FieldsFrom — helpers that carry
+//     a Pack and bound fields inside a context.Context, so they accumulate
+//     along a call chain and survive hand-offs to goroutines (see Go);
+//  4. Key — typed handles for custom Pack attributes (tenant, user,
+//     request ID, ...) with their own merge rules, JSON encoding and
+//     Extractor support.
 //
 // Implementations of Extractor live in runtime or integration packages
 // (e.g. an OTel-aware extractor). This package only defines the shape.
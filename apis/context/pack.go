@@ -22,12 +22,33 @@
 // can fill these fields from concrete sources.
 package context
 
+import (
+	"errors"
+	"fmt"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+)
+
+var (
+	// ErrAttrUnknown is returned by Pack.Set for names that are neither
+	// fixed attributes nor registered custom keys.
+	ErrAttrUnknown = errors.New("dlog: unknown pack attribute")
+
+	// ErrAttrType is returned by Pack.Set when a value does not have the
+	// type of the custom key.
+	ErrAttrType = errors.New("dlog: pack attribute type mismatch")
+)
+
 // Pack is a normalized set of well-known attributes that can be attached
 // to a log record. These fields mirror the canonical field names from
 // dlog (service, env, correlation_id, trace_id, span_id, ...).
 //
 // The struct is intended to be used as a plain value type: construct, fill,
 // and pass further. Callers should treat it as immutable once created.
+//
+// Besides the fixed attributes below, a Pack can carry custom attributes
+// (tenant, user, request ID, ...) registered with NewKey; see Key.
 type Pack struct {
 	// CorrelationID is an application-level correlation identifier.
 	// It is often propagated via HTTP/gRPC headers and is meant to bind
@@ -71,6 +92,10 @@ type Pack struct {
 
 	// Operation is the current operation/action name.
 	Operation string `json:"operation"`
+
+	// ext holds custom attributes set via Key.Set. It is immutable and
+	// shared between copies; nil means no custom attributes.
+	ext *attrSet
 }
 
 // Empty returns a zero-initialized Pack.
@@ -84,6 +109,8 @@ func Empty() Pack {
 // Rule:
 //   - for each string field, if b.<field> is not empty, it replaces a.<field>.
 //   - otherwise the original a.<field> value is kept.
+//   - custom attributes set in b are combined with those of a using the
+//     merge function of their Key (by default b's value replaces a's).
 //
 // This is useful when you have a "global" pack (service/env/node) and want
 // to enrich it with request-specific data (correlation, trace, operation).
@@ -127,11 +154,13 @@ func Merge(a, b Pack) Pack {
 	if b.Operation != "" {
 		out.Operation = b.Operation
 	}
+	out.ext = mergeAttrs(a.ext, b.ext)
 
 	return out
 }
 
-// IsZero reports whether all fields of the pack are empty.
+// IsZero reports whether all fields of the pack are empty and no custom
+// attributes are set.
 // This can be used by encoders to skip emitting an empty context section.
 func (p Pack) IsZero() bool {
 	return p.CorrelationID == "" &&
@@ -145,5 +174,159 @@ func (p Pack) IsZero() bool {
 		p.Region == "" &&
 		p.Component == "" &&
 		p.Subsystem == "" &&
-		p.Operation == ""
+		p.Operation == "" &&
+		p.ext.len() == 0
+}
+
+// Identity returns a copy of the pack without its request-scoped
+// attributes: CorrelationID, TraceID, SpanID and all custom attributes are
+// cleared, while the process and code-location attributes (service,
+// version, env, node, instance, region, component, subsystem, operation)
+// are kept. It is meant for records that summarize many requests, such as
+// throttling summaries, which must not claim to belong to any one of them.
+func (p Pack) Identity() Pack {
+	p.CorrelationID = ""
+	p.TraceID = ""
+	p.SpanID = ""
+	p.ext = nil
+	return p
+}
+
+// Lookup returns the attribute with the given name: a canonical field name
+// from apis/field/fields (e.g. "service", "trace_id", "instance_id", "op")
+// or the name of a custom attribute. Empty fixed attributes are reported as
+// missing.
+func (p Pack) Lookup(name string) (any, bool) {
+	if f := p.fixed(name); f != nil {
+		return *f, *f != ""
+	}
+	return p.Attr(name)
+}
+
+// Set returns a copy of p with the attribute named name set to v. Names
+// are resolved as in Lookup. Fixed attributes take strings; other values
+// are formatted with fmt.Sprint. Custom attributes must be registered
+// with NewKey and v must have the key's type.
+// p itself is not modified.
+func (p Pack) Set(name string, v any) (Pack, error) {
+	if f := p.fixed(name); f != nil {
+		if s, ok := v.(string); ok {
+			*f = s
+		} else {
+			*f = fmt.Sprint(v)
+		}
+		return p, nil
+	}
+	info := lookupKey(name)
+	if info == nil {
+		return p, fmt.Errorf("%w: %q", ErrAttrUnknown, name)
+	}
+	if !info.accepts(v) {
+		return p, fmt.Errorf("%w: %q cannot hold %T", ErrAttrType, name, v)
+	}
+	p.ext = p.ext.with(attr{key: info, value: v})
+	return p, nil
+}
+
+// Delete returns a copy of p without the attribute named name; names are
+// resolved as in Lookup. p itself is not modified.
+func (p Pack) Delete(name string) Pack {
+	if f := p.fixed(name); f != nil {
+		*f = ""
+		return p
+	}
+	p.ext = p.ext.without(name)
+	return p
+}
+
+// fixed returns a pointer to the fixed attribute with the given canonical
+// field name, or nil if name is not one.
+func (p *Pack) fixed(name string) *string {
+	switch name {
+	case fields.CorrelationID:
+		return &p.CorrelationID
+	case fields.TraceID:
+		return &p.TraceID
+	case fields.SpanID:
+		return &p.SpanID
+	case fields.Service:
+		return &p.Service
+	case fields.Version:
+		return &p.Version
+	case fields.Env:
+		return &p.Env
+	case fields.NodeID:
+		return &p.NodeID
+	case fields.InstanceID:
+		return &p.Instance
+	case fields.Region:
+		return &p.Region
+	case fields.Component:
+		return &p.Component
+	case fields.Subsystem:
+		return &p.Subsystem
+	case fields.Operation:
+		return &p.Operation
+	}
+	return nil
+}
+
+// attrAliases maps the JSON names of fixed attributes that differ from
+// their canonical field names.
+var attrAliases = map[string]string{
+	"instance":  fields.InstanceID,
+	"operation": fields.Operation,
+}
+
+// ResolveAttr resolves a user-supplied attribute name, as found in plugin
+// configurations and expressions: a canonical field name, the JSON name of
+// a fixed attribute ("instance", "operation") or the name of a registered
+// custom attribute. It returns the name expected by Lookup, Set and Delete
+// and reports whether name denotes an attribute at all.
+//
+// Custom attributes are registered at init time, so configurations should
+// be resolved when they are built, not when the package is loaded.
+func ResolveAttr(name string) (string, bool) {
+	if alias, ok := attrAliases[name]; ok {
+		return alias, true
+	}
+	var p Pack
+	if p.fixed(name) != nil || lookupKey(name) != nil {
+		return name, true
+	}
+	return name, false
+}
+
+// Fields projects the non-empty attributes of the pack into fields keyed by
+// the canonical names from apis/field/fields, followed by custom attributes
+// keyed by their registered names. The order is stable (declaration order of
+// Pack, then custom attributes by name), so encoders produce deterministic
+// output. An empty pack yields nil.
+func (p Pack) Fields() []field.Field {
+	if p.IsZero() {
+		return nil
+	}
+	out := make([]field.Field, 0, 12+p.ext.len())
+	add := func(key, value string) {
+		if value != "" {
+			out = append(out, field.New(key, value))
+		}
+	}
+	add(fields.CorrelationID, p.CorrelationID)
+	add(fields.TraceID, p.TraceID)
+	add(fields.SpanID, p.SpanID)
+	add(fields.Service, p.Service)
+	add(fields.Version, p.Version)
+	add(fields.Env, p.Env)
+	add(fields.NodeID, p.NodeID)
+	add(fields.InstanceID, p.Instance)
+	add(fields.Region, p.Region)
+	add(fields.Component, p.Component)
+	add(fields.Subsystem, p.Subsystem)
+	add(fields.Operation, p.Operation)
+	p.RangeAttrs(func(name string, value any) bool {
+		out = append(out, field.New(name, value))
+		return true
+	})
+	return out
 }
@@ -54,3 +54,11 @@ func New(key string, value any) Field {
 type Validator interface {
 	Validate() error
 }
+
+// Fielder is implemented by values that describe themselves as
+// structured fields, typically errors that carry context such as an HTTP
+// status or a resource ID. Error adapters and encoders include these
+// fields when rendering the value.
+type Fielder interface {
+	LogFields() []Field
+}
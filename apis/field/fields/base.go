@@ -82,6 +82,65 @@ const (
 	// for example "route", "issue_token", "list_users".
 	Operation = "op"
 
+	// Caller is the source location ("file:line") of the code that emitted
+	// the log entry.
+	Caller = "caller"
+
+	// Function is the fully qualified name of the function that emitted
+	// the log entry, e.g. "dirpx.dev/app/server.(*Server).Serve".
+	Function = "func"
+
+	// Stack is the call stack of the emitting goroutine, innermost frame
+	// first. It is usually captured only for severe levels.
+	Stack = "stack"
+
+	// Error is the error attached to the log entry.
+	Error = "error"
+
+	// ErrorType is the Go type name of the error, e.g. "*fs.PathError".
+	ErrorType = "error_type"
+
+	// ErrorCauses holds the structured causes of the error (the errors it
+	// wraps), for encoders that cannot nest them under Error.
+	ErrorCauses = "error_causes"
+
+	// ErrorStack is the stack trace carried by the error itself, as
+	// opposed to Stack, which is captured when the entry is logged.
+	ErrorStack = "error_stack"
+
+	// SampleRate is set on records kept by a probabilistic sampler and
+	// holds how many records each kept record represents (1/probability,
+	// e.g. 10 for 10% sampling), so backends can extrapolate counts.
+	SampleRate = "sample_rate"
+
+	// Suppressed is set on summary records emitted by throttling and
+	// rate-limiting plugins and holds how many records were suppressed
+	// since the previous summary.
+	Suppressed = "suppressed"
+
+	// Fingerprint is a stable identifier of a class of records (e.g. the
+	// hash of level, message and selected fields), set by deduplicating
+	// plugins so that backends can group repeats across process restarts.
+	Fingerprint = "fingerprint"
+
+	// RepeatCount is set on the follow-up record emitted by deduplicating
+	// plugins and holds how many repeats of the record were suppressed.
+	RepeatCount = "repeat_count"
+
+	// FirstSeen and LastSeen are set together with RepeatCount and hold the
+	// time of the first and of the last occurrence of the record.
+	FirstSeen = "first_seen"
+	LastSeen  = "last_seen"
+
+	// Truncated is set by size-limiting plugins on records they shortened
+	// and lists what was cut (e.g. "msg", a field key, "fields", "size").
+	// The leading underscore marks it as pipeline metadata.
+	Truncated = "_truncated"
+
+	// PipelineError lists the errors of plugins whose error policy is
+	// "annotate", as strings naming the failed stage.
+	PipelineError = "_pipeline_error"
+
 	// Message is the human-readable main text of the log entry.
 	// It should be short and descriptive, while additional context
 	// should go into structured fields.
@@ -0,0 +1,34 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package fields
+
+const (
+	// TenantID identifies the tenant on whose behalf the work is done
+	// in multi-tenant services.
+	TenantID = "tenant_id"
+
+	// UserID identifies the end user (or principal) that triggered the work.
+	UserID = "user_id"
+
+	// RequestID identifies a single inbound request. Unlike correlation_id
+	// it is not propagated to downstream services.
+	RequestID = "request_id"
+
+	// Cohort names the feature-flag cohort or experiment bucket the
+	// request was assigned to (for example "beta" or "control").
+	Cohort = "cohort"
+)
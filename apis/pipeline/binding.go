@@ -0,0 +1,39 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+)
+
+// SinkBinding describes the processing specific to one sink: the records
+// it receives after the shared Pre plugins and routing, and how they are
+// encoded.
+type SinkBinding struct {
+	// Pre is an ordered list of plugins that run only for this sink,
+	// after the shared Pre plugins (e.g. extra redaction for an external
+	// service). They see and modify a copy of the record.
+	Pre []plugin.Specification `json:"pre,omitempty" yaml:"pre,omitempty"`
+
+	// Encoder names the encoding of the sink's entries, e.g. "json" or
+	// "logfmt". If empty, the runtime default is used.
+	Encoder string `json:"encoder,omitempty" yaml:"encoder,omitempty"`
+
+	// MinLevel drops records below this level for this sink only.
+	MinLevel *level.Level `json:"minLevel,omitempty" yaml:"minLevel,omitempty"`
+}
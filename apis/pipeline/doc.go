@@ -30,7 +30,8 @@
 //     correlation/context injection).
 //  2. Encode the record (implementation detail, not part of this package).
 //  3. Deliver the encoded record to the configured sinks (also an
-//     implementation detail).
+//     implementation detail), or to the sinks selected by the first or
+//     every matching Route.
 //  4. Optionally run post-processing plugins (e.g. metrics taps).
 //
 // This separation lets runtime packages take a Specification produced from
@@ -31,7 +31,8 @@ import (
 type Pipeline interface {
 	// Emit pushes a single record through all stages.
 	// Implementations should:
-	//   1. stop on Decision=Drop;
+	//   1. stop on Decision=Drop or Decision=Defer, and bind stage.Deferrer
+	//      stages so that released records resume after the deferring stage;
 	//   2. collect/emit errors in a consistent way;
 	//   3. be safe for concurrent use (but that is runtime’s job).
 	Emit(ctx context.Context, r record.Record) error
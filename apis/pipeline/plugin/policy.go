@@ -0,0 +1,68 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package plugin
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorPolicy selects how the pipeline handles an error (or a recovered
+// panic) of a plugin while processing a record.
+//
+// An error the plugin returns together with stage.Continue and a non-zero
+// record is partial: the plugin processed the record but could not do all
+// of it. The returned record is kept under every policy but
+// OnErrorAbort, and OnErrorAnnotate records the error in it. The policies
+// below describe the handling of every other error, i.e. of failures.
+type ErrorPolicy string
+
+const (
+	// OnErrorFailClosed drops the record after a failure. The error is
+	// counted but not reported. It is the default, so that the failure of a plugin such as
+	// a redactor never lets an unprocessed record through.
+	OnErrorFailClosed ErrorPolicy = "fail_closed"
+
+	// OnErrorFailOpen continues with the record as it was before the
+	// plugin ran, also after a panic. The error is counted but not
+	// reported.
+	OnErrorFailOpen ErrorPolicy = "fail_open"
+
+	// OnErrorAbort drops the record and returns the error from
+	// Pipeline.Emit immediately.
+	OnErrorAbort ErrorPolicy = "abort"
+
+	// OnErrorAnnotate continues with the record returned by the plugin
+	// (or the record it received, if it returned none) and adds the error
+	// to its fields.PipelineError field. After a panic the record is
+	// dropped, since the plugin did not complete.
+	OnErrorAnnotate ErrorPolicy = "annotate"
+)
+
+var (
+	// ErrPolicyInvalid is returned for an unknown ErrorPolicy.
+	ErrPolicyInvalid = errors.New("dlog: invalid error policy")
+)
+
+// Validate reports whether p is empty or a known policy.
+func (p ErrorPolicy) Validate() error {
+	switch p {
+	case "", OnErrorFailClosed, OnErrorFailOpen, OnErrorAbort, OnErrorAnnotate:
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrPolicyInvalid, string(p))
+}
@@ -37,4 +37,15 @@ type Specification struct {
 	// The runtime knows how to interpret it based on Kind.
 	// We keep it as any to avoid leaking concrete types into apis.
 	Config any `json:"config,omitempty" yaml:"config,omitempty"`
+
+	// When optionally restricts the plugin to the records matching a
+	// condition written in the runtime expression language, e.g.
+	// `ctx.component == "ingress"` or `ctx.env == "prod"`. Other records
+	// pass the plugin untouched. The condition is evaluated per record,
+	// for enabled plugins only.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+
+	// OnError decides what happens to a record when the plugin returns an
+	// error or panics. If empty, OnErrorFailClosed is used.
+	OnError ErrorPolicy `json:"onError,omitempty" yaml:"onError,omitempty"`
 }
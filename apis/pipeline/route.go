@@ -0,0 +1,84 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RouteMode selects how many routes a record may take.
+type RouteMode string
+
+const (
+	// RouteFirst delivers a record to the sinks of the first matching
+	// route only. It is the default.
+	RouteFirst RouteMode = "first"
+
+	// RouteAll delivers a record to the sinks of every matching route,
+	// each sink at most once.
+	RouteAll RouteMode = "all"
+)
+
+var (
+	// ErrRouteInvalid is returned when a route declaration is rejected.
+	ErrRouteInvalid = errors.New("dlog: invalid route")
+)
+
+// Route sends the records matching a condition to a set of sinks.
+type Route struct {
+	// Name identifies the route in diagnostics. Optional.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// When is the condition, written in the runtime expression language,
+	// e.g. `level >= error` or `ctx.component == "audit"`.
+	When string `json:"when" yaml:"when"`
+
+	// Sinks are the names of the sinks receiving matching records.
+	Sinks []string `json:"sinks" yaml:"sinks"`
+}
+
+// Validate performs shallow validation of the routing declarations and
+// sink bindings: a known mode, routes with a condition and at least one
+// sink, and valid binding levels. Conditions are compiled and sink names,
+// encoders and plugins resolved by the runtime.
+func (s *Specification) Validate() error {
+	if s == nil {
+		return nil
+	}
+	switch s.RouteMode {
+	case "", RouteFirst, RouteAll:
+	default:
+		return fmt.Errorf("%w: unknown route mode %q", ErrRouteInvalid, s.RouteMode)
+	}
+	for i, r := range s.Routes {
+		switch {
+		case r.When == "":
+			return fmt.Errorf("%w: routes[%d] %q: when is required", ErrRouteInvalid, i, r.Name)
+		case len(r.Sinks) == 0:
+			return fmt.Errorf("%w: routes[%d] %q: no sinks", ErrRouteInvalid, i, r.Name)
+		}
+	}
+	for name, b := range s.Bindings {
+		if b.MinLevel != nil {
+			if err := b.MinLevel.Validate(); err != nil {
+				return fmt.Errorf("dlog: binding %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
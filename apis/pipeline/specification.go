@@ -28,9 +28,11 @@ import (
 //
 // Typical order is:
 //  1. pre plugins  (redact, sampling, throttle, inject)
-//  2. encoder      (implementation detail, not in spec)
-//  3. sinks        (one or many)
-//  4. post plugins (metrics, debug taps, etc.)
+//  2. routing      (which sinks receive the record)
+//  3. per-sink pre plugins and minimum level (Bindings)
+//  4. encoder      (chosen per sink, default is an implementation detail)
+//  5. sinks        (one or many)
+//  6. post plugins (metrics, debug taps, etc.)
 type Specification struct {
 	// Pre is an ordered list of plugins that run before encoding/sinking.
 	// Use this for things that may DROP the record or mutate sensitive data.
@@ -43,5 +45,20 @@ type Specification struct {
 	// Sinks is a list of sink IDs/names that the runtime must fan-out to.
 	// Actual sink configs live elsewhere (in the top-level dlog config),
 	// here we just reference them by name.
+	//
+	// When Routes are declared, Sinks is the default route: it receives
+	// the records that match no route. Leave it empty to discard them.
 	Sinks []string `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+
+	// Routes select sinks per record by condition, evaluated in order
+	// after the pre plugins.
+	Routes []Route `json:"routes,omitempty" yaml:"routes,omitempty"`
+
+	// RouteMode is RouteFirst (default) or RouteAll.
+	RouteMode RouteMode `json:"routeMode,omitempty" yaml:"routeMode,omitempty"`
+
+	// Bindings maps sink names to sink-specific plugins, encoders and
+	// minimum levels. Sinks without a binding get the records as they
+	// leave the shared Pre plugins, in the default encoding.
+	Bindings map[string]SinkBinding `json:"bindings,omitempty" yaml:"bindings,omitempty"`
 }
@@ -28,4 +28,10 @@ const (
 	// processing this record. This is typically used by sampling, throttling,
 	// rate-limit or security plugins (e.g. redact-and-drop).
 	Drop
+
+	// Defer means the stage has taken ownership of the record and may hand
+	// it back later (or never) through the Emitter it was bound with; see
+	// Deferrer. Like Drop, the pipeline stops processing the record now.
+	// Only stages implementing Deferrer may return Defer.
+	Defer
 )
@@ -0,0 +1,62 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stage
+
+import (
+	"context"
+
+	"dirpx.dev/dlog/apis/record"
+)
+
+// Emitter hands records held by a stage back to the pipeline.
+//
+// Released records resume processing at the stage that follows the one
+// that deferred them, in the order given. Emit is synchronous: when it
+// returns, the records have been through the rest of the pipeline, so a
+// stage that calls Emit from inside Process delivers the released records
+// ahead of the record it is processing.
+//
+// Emit returns the errors of the released records. They do not concern
+// the record being processed, so a stage must not return them from
+// Process; pipelines account for them on their own (runtime/pipeline.Chain
+// counts them per stage).
+type Emitter interface {
+	Emit(ctx context.Context, rs ...record.Record) error
+}
+
+// EmitterFunc adapts a function to the Emitter interface.
+type EmitterFunc func(ctx context.Context, rs ...record.Record) error
+
+// Emit calls f(ctx, rs...).
+func (f EmitterFunc) Emit(ctx context.Context, rs ...record.Record) error { return f(ctx, rs...) }
+
+// Deferrer is implemented by stages that may return Defer, i.e. hold
+// records and release them later (tail-based sampling, reordering,
+// batching), and by stages that emit records of their own, such as
+// summaries of suppressed records.
+//
+// The pipeline calls Bind exactly once, before the first Process call,
+// with the Emitter for the stage's position in that pipeline. A Deferrer
+// instance therefore belongs to a single pipeline. Pipelines that do not
+// support deferral must reject Deferrer stages at build time rather than
+// silently lose records.
+type Deferrer interface {
+	Stage
+
+	// Bind gives the stage the Emitter used to release held records.
+	Bind(e Emitter)
+}
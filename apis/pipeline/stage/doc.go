@@ -34,6 +34,8 @@
 //     pipeline — that's why every stage exposes Enabled().
 //  3. A stage must clearly signal what to do with the record — that's
 //     why Process(...) returns a Decision (Continue or Drop).
+//  4. A stage may hold a record back and release it later (Defer); such
+//     stages implement Deferrer and receive an Emitter from the pipeline.
 //
 // Implementations are expected to be safe for concurrent use if the
 // runtime executes the pipeline from multiple goroutines.
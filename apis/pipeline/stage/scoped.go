@@ -0,0 +1,36 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stage
+
+// SinkScoped is implemented by stages whose behaviour depends on the sink
+// the record is delivered to, such as per-sink size limits.
+//
+// Pipelines that run a separate chain per sink call ForSink once per sink,
+// at build time, and run the returned variant in that sink's chain.
+// Chains that run before the sinks are known cannot honour per-sink
+// settings, so they must reject a stage whose Sinks is not empty rather
+// than silently ignore its settings.
+type SinkScoped interface {
+	Stage
+
+	// ForSink returns the variant of the stage for the named sink, or the
+	// stage itself if it has no settings for that sink.
+	ForSink(name string) Stage
+
+	// Sinks returns the names of the sinks with specific settings.
+	Sinks() []string
+}
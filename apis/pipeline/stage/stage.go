@@ -24,8 +24,9 @@ import (
 
 // Stage is the minimal processing unit in the pipeline.
 // A stage receives a record and returns (possibly) a modified record
-// plus a decision. Errors are allowed and should be handled by the pipeline
-// implementation (e.g. log, count, continue, or stop — up to runtime).
+// plus a decision. Errors are allowed and are handled by the pipeline
+// implementation according to the plugin's error policy
+// (plugin.Specification.OnError).
 //
 // This interface is intentionally small so that plugins and other
 // processing components can all implement the same shape.
@@ -37,8 +38,15 @@ type Stage interface {
 	// and other request-scoped values across API boundaries and between
 	// processes.
 	//
-	// If an error is returned, the pipeline implementation should decide
-	// how to handle it (e.g. log, count, continue, or stop — up to runtime).
+	// If an error is returned, the pipeline implementation handles it
+	// according to the plugin's error policy: continue with the returned or
+	// the original record, drop the record, abort the emit, or annotate the
+	// record (see plugin.ErrorPolicy). Panics are treated as errors. An
+	// error returned with Continue and a non-zero record is partial and
+	// does not cost the record its delivery, so stages should return one
+	// when they processed the record only in part, and keep errors that
+	// do not concern the record (such as those of an Emitter) out of
+	// Process results altogether.
 	//
 	// The returned record can be the same as the input record (i.e. no modification).
 	//
@@ -59,8 +59,9 @@ func (s *Specification) Validate() error {
 			}
 		}
 	}
-	// Pipeline/Sinks validation is deferred to runtime (registry-aware).
-	return nil
+	// Routing declarations are checked shallowly; conditions and sink
+	// names are validated by runtime (registry-aware).
+	return s.Pipeline.Validate()
 }
 
 // Merge applies override over base according to provider precedence.
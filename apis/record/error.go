@@ -0,0 +1,178 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package record
+
+import (
+	"fmt"
+	"reflect"
+
+	"dirpx.dev/dlog/apis/field"
+)
+
+// MaxErrorDepth bounds how deep DefaultErrorAdapter follows wrapped errors.
+const MaxErrorDepth = 16
+
+// ErrorInfo is the structured projection of an error: its message, its Go
+// type, the fields and stack it carries and the errors it wraps.
+type ErrorInfo struct {
+	// Message is err.Error().
+	Message string
+	// Type is the Go type name, e.g. "*fs.PathError".
+	Type string
+	// Fields are the fields reported by the error (see field.Fielder).
+	Fields []field.Field
+	// Stack is the stack trace carried by the error, if any.
+	Stack []Frame
+	// Causes are the projections of the wrapped errors: one for
+	// Unwrap() error, several for Unwrap() []error (errors.Join).
+	Causes []ErrorInfo
+}
+
+// IsZero reports whether the projection is empty (err was nil).
+func (e ErrorInfo) IsZero() bool {
+	return e.Message == "" && e.Type == ""
+}
+
+// Walk calls fn for e and every cause, depth-first, until fn returns false.
+func (e ErrorInfo) Walk(fn func(ErrorInfo) bool) bool {
+	if !fn(e) {
+		return false
+	}
+	for _, c := range e.Causes {
+		if !c.Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllFields returns the fields of e and all its causes, outermost first.
+func (e ErrorInfo) AllFields() []field.Field {
+	var out []field.Field
+	e.Walk(func(i ErrorInfo) bool {
+		out = append(out, i.Fields...)
+		return true
+	})
+	return out
+}
+
+// FirstStack returns the outermost stack trace found in e or its causes.
+func (e ErrorInfo) FirstStack() []Frame {
+	var out []Frame
+	e.Walk(func(i ErrorInfo) bool {
+		out = i.Stack
+		return len(out) == 0
+	})
+	return out
+}
+
+// AdaptedError is an error whose structured projection is already known,
+// typically because a pipeline stage rewrote it (e.g. redacted the fields
+// of an error before encoding). AdaptError and the runtime encoders use
+// Info as is instead of inspecting the error again. It deliberately does
+// not unwrap to the error it was derived from.
+type AdaptedError struct {
+	Info ErrorInfo
+}
+
+// Error returns Info.Message.
+func (e *AdaptedError) Error() string { return e.Info.Message }
+
+// ErrorAdapter projects errors into their structured form. Encoders use
+// it to render Record.Err.
+type ErrorAdapter interface {
+	Adapt(err error) ErrorInfo
+}
+
+// ErrorAdapterFunc adapts a function to the ErrorAdapter interface.
+type ErrorAdapterFunc func(err error) ErrorInfo
+
+// Adapt calls f(err).
+func (f ErrorAdapterFunc) Adapt(err error) ErrorInfo { return f(err) }
+
+// DefaultErrorAdapter walks the errors.Unwrap / errors.Join tree of an
+// error (up to MaxErrorDepth levels) and collects, for every node:
+//
+//   - fields from field.Fielder;
+//   - a stack trace from a StackFrames() []Frame method, a
+//     Callers() []uintptr method, or a StackTrace() method returning a
+//     slice of program counters (as in github.com/pkg/errors).
+var DefaultErrorAdapter ErrorAdapter = ErrorAdapterFunc(AdaptError)
+
+// AdaptError implements DefaultErrorAdapter. A nil err yields a zero
+// ErrorInfo; an *AdaptedError yields its Info.
+func AdaptError(err error) ErrorInfo {
+	return adaptError(err, MaxErrorDepth)
+}
+
+func adaptError(err error, depth int) ErrorInfo {
+	if err == nil {
+		return ErrorInfo{}
+	}
+	if a, ok := err.(*AdaptedError); ok {
+		return a.Info
+	}
+	info := ErrorInfo{Message: err.Error(), Type: fmt.Sprintf("%T", err)}
+	if f, ok := err.(field.Fielder); ok {
+		info.Fields = f.LogFields()
+	}
+	info.Stack = errorStack(err)
+
+	if depth <= 1 {
+		return info
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		if c := u.Unwrap(); c != nil {
+			info.Causes = []ErrorInfo{adaptError(c, depth-1)}
+		}
+	case interface{ Unwrap() []error }:
+		for _, c := range u.Unwrap() {
+			if c != nil {
+				info.Causes = append(info.Causes, adaptError(c, depth-1))
+			}
+		}
+	}
+	return info
+}
+
+// errorStack extracts the stack trace carried by err, if any.
+func errorStack(err error) []Frame {
+	switch s := err.(type) {
+	case interface{ StackFrames() []Frame }:
+		return s.StackFrames()
+	case interface{ Callers() []uintptr }:
+		return framesOf(s.Callers())
+	}
+
+	// StackTrace() T where T is a slice of an integer type holding
+	// program counters. Matched by reflection to avoid a dependency.
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	v := m.Call(nil)[0]
+	pcs := make([]uintptr, v.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(v.Index(i).Uint())
+	}
+	return framesOf(pcs)
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package record
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// Frame is a resolved source location.
+type Frame struct {
+	// Function is the fully qualified function name, e.g. "main.run".
+	Function string `json:"function,omitempty"`
+	// File is the absolute path of the source file.
+	File string `json:"file,omitempty"`
+	// Line is the line number in File.
+	Line int `json:"line,omitempty"`
+}
+
+// IsZero reports whether the frame is unset.
+func (f Frame) IsZero() bool {
+	return f.Function == "" && f.File == "" && f.Line == 0
+}
+
+// String returns "file:line", or "" for a zero frame.
+func (f Frame) String() string {
+	if f.File == "" {
+		return ""
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+// FrameOf resolves a program counter as returned by runtime.Callers.
+// A zero pc yields a zero Frame.
+func FrameOf(pc uintptr) Frame {
+	if pc == 0 {
+		return Frame{}
+	}
+	fr, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return Frame{Function: fr.Function, File: fr.File, Line: fr.Line}
+}
+
+// CallerPC returns the program counter of the function skip levels above
+// the caller of CallerPC (0 identifies the caller itself), or 0.
+// Resolving it with FrameOf is deferred so that records which are dropped
+// never pay for symbolization.
+func CallerPC(skip int) uintptr {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return 0
+	}
+	return pcs[0]
+}
+
+// Stack returns up to depth frames of the current goroutine's stack,
+// innermost first, starting skip levels above the caller of Stack.
+func Stack(skip, depth int) []Frame {
+	if depth <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip+2, pcs)
+	return framesOf(pcs[:n])
+}
+
+// framesOf resolves program counters as returned by runtime.Callers,
+// expanding inlined calls.
+func framesOf(pcs []uintptr) []Frame {
+	if len(pcs) == 0 {
+		return nil
+	}
+	out := make([]Frame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		fr, more := frames.Next()
+		out = append(out, Frame{Function: fr.Function, File: fr.File, Line: fr.Line})
+		if !more {
+			break
+		}
+	}
+	return out
+}
@@ -42,6 +42,10 @@ type Record struct {
 	Fields []field.Field
 	// Err is the original error, if any (implementations may project it via ErrorAdapter)
 	Err error
+	// Caller is the source location that emitted the record; zero if not captured
+	Caller Frame
+	// Stack is the emitting goroutine's call stack, innermost first; nil if not captured
+	Stack []Frame
 }
 
 // NewRecord builds a Record with the required parts.
@@ -79,6 +83,13 @@ func (r Record) Validate() error {
 	return nil
 }
 
+// IsZero reports whether r is the zero Record, as returned alongside an
+// error by stages that could not produce a result.
+func (r Record) IsZero() bool {
+	return r.Time.IsZero() && r.Level == 0 && r.Message == "" && r.Ctx.IsZero() &&
+		r.Fields == nil && r.Err == nil && r.Caller.IsZero() && r.Stack == nil
+}
+
 // WithFields returns a shallow copy of the record with additional fields appended.
 // This is useful for plugins that want to enrich the record while keeping the
 // original value semantics.
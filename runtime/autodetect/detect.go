@@ -0,0 +1,268 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package autodetect
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field/fields"
+)
+
+const (
+	// DefaultCgroupPath is the file inspected first for a container ID.
+	DefaultCgroupPath = "/proc/self/cgroup"
+
+	// DefaultMountinfoPath is the file inspected for a container ID when
+	// the cgroup file has none, as is the case with cgroup v2.
+	DefaultMountinfoPath = "/proc/self/mountinfo"
+)
+
+var (
+	// ErrEnvKey is returned by Options.Validate for an Env key that does
+	// not name a string Pack attribute.
+	ErrEnvKey = errors.New("dlog: invalid autodetect env key")
+)
+
+// Options configures detection.
+type Options struct {
+	// Env maps Pack attribute names to the environment variables
+	// consulted for that attribute, in order; the first non-empty
+	// variable wins. If nil, DefaultEnv is used.
+	// Keys are resolved by context.ResolveAttr: any fixed attribute by
+	// canonical or JSON name ("instance_id" or "instance", "op" or
+	// "operation", ...) or a registered custom attribute of type string.
+	// Detect ignores other keys; Validate reports them.
+	Env map[string][]string
+
+	// DisableBuildInfo skips reading the version from build info.
+	DisableBuildInfo bool
+
+	// DisableHost skips hostname and cgroup detection.
+	DisableHost bool
+
+	// CgroupPath overrides DefaultCgroupPath.
+	CgroupPath string
+
+	// MountinfoPath overrides DefaultMountinfoPath.
+	MountinfoPath string
+}
+
+// Validate reports Env keys that Detect would ignore.
+func (o Options) Validate() error {
+	keys := make([]string, 0, len(o.Env))
+	for key := range o.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, ok := dlogctx.ResolveAttr(key)
+		if !ok {
+			return fmt.Errorf("%w: %q is not a context attribute", ErrEnvKey, key)
+		}
+		if _, err := (dlogctx.Pack{}).Set(name, ""); err != nil {
+			return fmt.Errorf("%w: %w", ErrEnvKey, err)
+		}
+	}
+	return nil
+}
+
+// DefaultEnv returns the default attribute to environment variable mapping.
+// Options.Env accepts more keys than these; see its documentation.
+func DefaultEnv() map[string][]string {
+	return map[string][]string{
+		fields.Service:    {"SERVICE_NAME", "OTEL_SERVICE_NAME"},
+		fields.Version:    {"SERVICE_VERSION"},
+		fields.Env:        {"DEPLOY_ENV", "ENVIRONMENT"},
+		fields.NodeID:     {"NODE_NAME"},
+		fields.InstanceID: {"POD_NAME", "HOSTNAME"},
+		fields.Region:     {"REGION", "AWS_REGION"},
+	}
+}
+
+// Detect inspects the process environment and returns the detected Pack.
+// It performs I/O on every call; prefer Extractor for repeated use.
+// A nil opts is equivalent to the zero Options.
+func Detect(opts *Options) dlogctx.Pack {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+
+	var p dlogctx.Pack
+	if !o.DisableBuildInfo {
+		p.Version = buildVersion()
+	}
+	if !o.DisableHost {
+		if h, err := os.Hostname(); err == nil {
+			p.NodeID = h
+		}
+		cgroup, mountinfo := o.CgroupPath, o.MountinfoPath
+		if cgroup == "" {
+			cgroup = DefaultCgroupPath
+		}
+		if mountinfo == "" {
+			mountinfo = DefaultMountinfoPath
+		}
+		p.Instance = cgroupContainerID(cgroup)
+		if p.Instance == "" {
+			p.Instance = mountinfoContainerID(mountinfo)
+		}
+	}
+
+	env := o.Env
+	if env == nil {
+		env = DefaultEnv()
+	}
+	return dlogctx.Merge(p, fromEnv(env))
+}
+
+// Extractor returns a static Extractor whose Pack is detected once,
+// on first use, and then reused for every call.
+// A nil opts is equivalent to the zero Options.
+func Extractor(opts *Options) dlogctx.Extractor {
+	detect := sync.OnceValue(func() dlogctx.Pack {
+		return Detect(opts)
+	})
+	return dlogctx.ExtractorFunc(func(context.Context) dlogctx.Pack {
+		return detect()
+	})
+}
+
+// buildVersion returns the VCS revision embedded in the binary, suffixed
+// with "-dirty" for modified trees, or the main module version.
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var rev string
+	var dirty bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if rev != "" {
+		if dirty {
+			rev += "-dirty"
+		}
+		return rev
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	return ""
+}
+
+// containerIDPattern matches the 64 hex character IDs used by Docker,
+// containerd and CRI-O in cgroup paths.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// cgroupContainerID returns the first container ID found in the cgroup
+// file. Under cgroup v1 the paths name the container; under cgroup v2
+// the container usually sees only "0::/".
+func cgroupContainerID(path string) string {
+	var id string
+	scanLines(path, func(line string) bool {
+		id = containerIDPattern.FindString(line)
+		return id == ""
+	})
+	return id
+}
+
+// containerFiles are the files container runtimes bind-mount from the
+// container's own directory, whose host path holds the container ID.
+var containerFiles = map[string]bool{
+	"/etc/hostname":    true,
+	"/etc/hosts":       true,
+	"/etc/resolv.conf": true,
+}
+
+// mountinfoContainerID returns the container ID found in the source path
+// of a bind mount of one of containerFiles, as listed by the mountinfo
+// file; Docker and Podman mount them from directories named after the
+// container. Other runtimes, such as the Kubernetes CRI ones, mount them
+// from per-pod directories, which yield no ID.
+func mountinfoContainerID(path string) string {
+	var id string
+	scanLines(path, func(line string) bool {
+		// ID parent major:minor root mount-point ...
+		f := strings.Fields(line)
+		if len(f) < 5 || !containerFiles[f[4]] {
+			return true
+		}
+		id = containerIDPattern.FindString(f[3])
+		return id == ""
+	})
+	return id
+}
+
+// scanLines calls fn for every line of the file at path until fn returns
+// false. A missing or unreadable file has no lines.
+func scanLines(path string, fn func(line string) bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() && fn(sc.Text()) {
+	}
+}
+
+// fromEnv builds a Pack from the environment variable mapping. Keys are
+// applied in sorted order, so of two keys naming the same attribute
+// ("instance" and "instance_id"), the later one wins; keys that are not
+// string attributes are skipped.
+func fromEnv(env map[string][]string) dlogctx.Pack {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var p dlogctx.Pack
+	for _, key := range keys {
+		name, ok := dlogctx.ResolveAttr(key)
+		if !ok {
+			continue
+		}
+		for _, v := range env[key] {
+			if v = os.Getenv(v); v != "" {
+				if q, err := p.Set(name, v); err == nil {
+					p = q
+				}
+				break
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package autodetect fills the static, process-level part of a context
+// Pack (service, version, env, node, instance, region) from the runtime
+// environment instead of hand-written configuration.
+//
+// Sources, from lowest to highest precedence:
+//
+//  1. Build info: Version from the vcs.revision setting embedded by the
+//     Go toolchain (runtime/debug.ReadBuildInfo).
+//  2. Host: NodeID from os.Hostname; Instance from the container ID found
+//     in /proc/self/cgroup (cgroup v1) or, failing that, in the bind
+//     mounts of /proc/self/mountinfo (Docker and Podman with cgroup v2).
+//  3. Environment variables, mapped per attribute (see DefaultEnv), for
+//     example SERVICE_NAME -> service or POD_NAME -> instance_id.
+//     Options.Validate rejects mappings for names that are not string
+//     Pack attributes, which Detect would ignore.
+//
+// Detection runs once; the resulting Extractor is static and is meant to
+// be the first element of a context.Chain.
+package autodetect
@@ -0,0 +1,36 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package encoder turns records into the encoded entries consumed by
+// sinks.
+//
+// Two encoders are provided: JSON (one object per line) and Logfmt
+// (key=value pairs). Both write the same keys in the same order:
+//
+//	ts, level, msg, caller, func,
+//	Pack attributes (context.Pack.Fields), record fields,
+//	error, stack
+//
+// using the canonical names from apis/field/fields. Empty parts (zero
+// time, zero caller, nil error, no stack) are omitted.
+//
+// Errors are projected with a record.ErrorAdapter (by default
+// record.DefaultErrorAdapter). JSON renders the projection as a nested
+// object (msg, type, fields, stack, causes); logfmt renders the message
+// under "error" followed by error_type, "error.<key>" fields, error_stack
+// and error_causes (as JSON). Options.FlatErrors restores the plain
+// message.
+package encoder
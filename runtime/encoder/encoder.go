@@ -0,0 +1,88 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package encoder
+
+import (
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"dirpx.dev/dlog/apis/record"
+)
+
+// Encoder serializes records into sink entries. Implementations must be
+// safe for concurrent use.
+type Encoder interface {
+	// Name identifies the encoding, e.g. "json".
+	Name() string
+
+	// Append appends the encoding of r, terminated by a newline, to dst
+	// and returns the extended buffer.
+	Append(dst []byte, r record.Record) ([]byte, error)
+}
+
+// Options configures the built-in encoders.
+type Options struct {
+	// TimeLayout formats the record time and time.Time values, which are
+	// converted to UTC first. If empty, time.RFC3339Nano is used.
+	TimeLayout string
+
+	// FullCaller renders the caller with its full file path instead of
+	// the last directory and file name ("server/http.go:42").
+	FullCaller bool
+
+	// ErrorAdapter projects Record.Err into its structured form (message,
+	// type, fields, stack and causes). If nil, record.DefaultErrorAdapter
+	// is used. A *record.AdaptedError is rendered from its Info without
+	// calling the adapter.
+	ErrorAdapter record.ErrorAdapter
+
+	// FlatErrors renders only the error message, as a plain string.
+	FlatErrors bool
+}
+
+// layout returns the effective time layout.
+func (o *Options) layout() string {
+	if o.TimeLayout != "" {
+		return o.TimeLayout
+	}
+	return time.RFC3339Nano
+}
+
+// caller renders f according to the options.
+func (o *Options) caller(f record.Frame) string {
+	if f.File == "" || o.FullCaller {
+		return f.String()
+	}
+	dir, file := filepath.Split(f.File)
+	short := filepath.Base(dir) + "/" + file
+	if dir == "" {
+		short = file
+	}
+	return short + ":" + strconv.Itoa(f.Line)
+}
+
+// adapt projects err with the configured adapter.
+func (o *Options) adapt(err error) record.ErrorInfo {
+	if a, ok := err.(*record.AdaptedError); ok {
+		return a.Info
+	}
+	if o.ErrorAdapter != nil {
+		return o.ErrorAdapter.Adapt(err)
+	}
+	return record.DefaultErrorAdapter.Adapt(err)
+}
@@ -0,0 +1,246 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package encoder
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// JSON encodes records as single-line JSON objects.
+type JSON struct {
+	opts Options
+}
+
+var _ Encoder = (*JSON)(nil)
+
+// NewJSON returns a JSON encoder. A nil opts is equivalent to the zero
+// Options.
+func NewJSON(opts *Options) *JSON {
+	e := &JSON{}
+	if opts != nil {
+		e.opts = *opts
+	}
+	return e
+}
+
+// Name returns "json".
+func (e *JSON) Name() string { return "json" }
+
+// Append appends r as a JSON object followed by a newline.
+func (e *JSON) Append(dst []byte, r record.Record) ([]byte, error) {
+	dst = append(dst, '{')
+	first := true
+	key := func(k string) {
+		if !first {
+			dst = append(dst, ',')
+		}
+		first = false
+		dst = appendJSONString(dst, k)
+		dst = append(dst, ':')
+	}
+
+	if !r.Time.IsZero() {
+		key(fields.Timestamp)
+		dst = appendJSONString(dst, r.Time.UTC().Format(e.opts.layout()))
+	}
+	key(fields.Level)
+	dst = appendJSONString(dst, r.Level.String())
+	key(fields.Message)
+	dst = appendJSONString(dst, r.Message)
+	if !r.Caller.IsZero() {
+		if r.Caller.File != "" {
+			key(fields.Caller)
+			dst = appendJSONString(dst, e.opts.caller(r.Caller))
+		}
+		if r.Caller.Function != "" {
+			key(fields.Function)
+			dst = appendJSONString(dst, r.Caller.Function)
+		}
+	}
+	for _, f := range r.Ctx.Fields() {
+		key(f.Key)
+		dst = e.appendValue(dst, f.Value)
+	}
+	for _, f := range r.Fields {
+		key(f.Key)
+		dst = e.appendValue(dst, f.Value)
+	}
+	if r.Err != nil {
+		key(fields.Error)
+		if e.opts.FlatErrors {
+			dst = appendJSONString(dst, r.Err.Error())
+		} else {
+			dst = e.appendErrorInfo(dst, e.opts.adapt(r.Err))
+		}
+	}
+	if len(r.Stack) > 0 {
+		key(fields.Stack)
+		dst = e.appendValue(dst, r.Stack)
+	}
+	return append(dst, '}', '\n'), nil
+}
+
+// appendErrorInfo appends info as an object with the keys msg, type,
+// fields, stack and causes; empty parts are omitted.
+func (e *JSON) appendErrorInfo(dst []byte, info record.ErrorInfo) []byte {
+	dst = append(dst, `{"msg":`...)
+	dst = appendJSONString(dst, info.Message)
+	dst = append(dst, `,"type":`...)
+	dst = appendJSONString(dst, info.Type)
+	if len(info.Fields) > 0 {
+		dst = append(dst, `,"fields":{`...)
+		for i, f := range info.Fields {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendJSONString(dst, f.Key)
+			dst = append(dst, ':')
+			dst = e.appendValue(dst, f.Value)
+		}
+		dst = append(dst, '}')
+	}
+	if len(info.Stack) > 0 {
+		dst = append(dst, `,"stack":`...)
+		dst = e.appendValue(dst, info.Stack)
+	}
+	if len(info.Causes) > 0 {
+		dst = append(dst, `,"causes":[`...)
+		for i, c := range info.Causes {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = e.appendErrorInfo(dst, c)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+// appendValue appends v as JSON. Common scalar types are encoded
+// directly; everything else goes through encoding/json, falling back to
+// its fmt representation when it cannot be marshaled.
+func (e *JSON) appendValue(dst []byte, v any) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(dst, "null"...)
+	case string:
+		return appendJSONString(dst, x)
+	case bool:
+		return strconv.AppendBool(dst, x)
+	case int:
+		return strconv.AppendInt(dst, int64(x), 10)
+	case int8:
+		return strconv.AppendInt(dst, int64(x), 10)
+	case int16:
+		return strconv.AppendInt(dst, int64(x), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(x), 10)
+	case int64:
+		return strconv.AppendInt(dst, x, 10)
+	case uint:
+		return strconv.AppendUint(dst, uint64(x), 10)
+	case uint8:
+		return strconv.AppendUint(dst, uint64(x), 10)
+	case uint16:
+		return strconv.AppendUint(dst, uint64(x), 10)
+	case uint32:
+		return strconv.AppendUint(dst, uint64(x), 10)
+	case uint64:
+		return strconv.AppendUint(dst, x, 10)
+	case float32:
+		return appendJSONFloat(dst, float64(x), 32)
+	case float64:
+		return appendJSONFloat(dst, x, 64)
+	case time.Time:
+		return appendJSONString(dst, x.UTC().Format(e.opts.layout()))
+	case time.Duration:
+		return appendJSONString(dst, x.String())
+	case error:
+		return appendJSONString(dst, x.Error())
+	case json.Marshaler, encoding.TextMarshaler:
+		// handled by encoding/json below
+	case fmt.Stringer:
+		return appendJSONString(dst, x.String())
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return appendJSONString(dst, fmt.Sprintf("%+v", v))
+	}
+	return append(dst, b...)
+}
+
+// appendJSONFloat appends f, encoding non-finite values as strings.
+func appendJSONFloat(dst []byte, f float64, bits int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendJSONString(dst, strconv.FormatFloat(f, 'g', -1, bits))
+	}
+	return strconv.AppendFloat(dst, f, 'g', -1, bits)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string. Invalid UTF-8 is replaced
+// by U+FFFD.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
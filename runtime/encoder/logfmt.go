@@ -0,0 +1,187 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package encoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// Logfmt encodes records as logfmt lines (key=value pairs separated by
+// spaces). Values that are not scalars are rendered as JSON.
+type Logfmt struct {
+	opts Options
+	json JSON
+}
+
+var _ Encoder = (*Logfmt)(nil)
+
+// NewLogfmt returns a logfmt encoder. A nil opts is equivalent to the
+// zero Options.
+func NewLogfmt(opts *Options) *Logfmt {
+	e := &Logfmt{}
+	if opts != nil {
+		e.opts = *opts
+	}
+	e.json.opts = e.opts
+	return e
+}
+
+// Name returns "logfmt".
+func (e *Logfmt) Name() string { return "logfmt" }
+
+// Append appends r as a logfmt line followed by a newline.
+func (e *Logfmt) Append(dst []byte, r record.Record) ([]byte, error) {
+	start := len(dst)
+	kv := func(k, v string) {
+		if len(dst) > start {
+			dst = append(dst, ' ')
+		}
+		dst = appendLogfmtKey(dst, k)
+		dst = append(dst, '=')
+		dst = appendLogfmtString(dst, v)
+	}
+
+	if !r.Time.IsZero() {
+		kv(fields.Timestamp, r.Time.UTC().Format(e.opts.layout()))
+	}
+	kv(fields.Level, r.Level.String())
+	kv(fields.Message, r.Message)
+	if r.Caller.File != "" {
+		kv(fields.Caller, e.opts.caller(r.Caller))
+	}
+	if r.Caller.Function != "" {
+		kv(fields.Function, r.Caller.Function)
+	}
+	for _, f := range r.Ctx.Fields() {
+		kv(f.Key, e.format(f.Value))
+	}
+	for _, f := range r.Fields {
+		kv(f.Key, e.format(f.Value))
+	}
+	if r.Err != nil {
+		kv(fields.Error, r.Err.Error())
+		if !e.opts.FlatErrors {
+			info := e.opts.adapt(r.Err)
+			kv(fields.ErrorType, info.Type)
+			for _, f := range info.AllFields() {
+				kv(fields.Error+"."+f.Key, e.format(f.Value))
+			}
+			if st := info.FirstStack(); len(st) > 0 {
+				kv(fields.ErrorStack, formatStack(st))
+			}
+			if len(info.Causes) > 0 {
+				b := append([]byte(nil), '[')
+				for i, c := range info.Causes {
+					if i > 0 {
+						b = append(b, ',')
+					}
+					b = e.json.appendErrorInfo(b, c)
+				}
+				kv(fields.ErrorCauses, string(append(b, ']')))
+			}
+		}
+	}
+	if len(r.Stack) > 0 {
+		kv(fields.Stack, formatStack(r.Stack))
+	}
+	return append(dst, '\n'), nil
+}
+
+// formatStack renders frames one per line as "function file:line".
+func formatStack(st []record.Frame) string {
+	var b strings.Builder
+	for i, fr := range st {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(fr.Function)
+		b.WriteByte(' ')
+		b.WriteString(fr.String())
+	}
+	return b.String()
+}
+
+// format renders a field value as text.
+func (e *Logfmt) format(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return x
+	case bool:
+		return strconv.FormatBool(x)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case uint64:
+		return strconv.FormatUint(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	case time.Time:
+		return x.UTC().Format(e.opts.layout())
+	case time.Duration:
+		return x.String()
+	case error:
+		return x.Error()
+	case int8, int16, int32, uint, uint8, uint16, uint32, float32:
+		return fmt.Sprint(x)
+	case json.Marshaler:
+		// handled below
+	case fmt.Stringer:
+		return x.String()
+	}
+	return string(e.json.appendValue(nil, v))
+}
+
+// appendLogfmtKey appends k with characters that would break the
+// key=value syntax replaced by '_'.
+func appendLogfmtKey(dst []byte, k string) []byte {
+	if k == "" {
+		return append(dst, '_')
+	}
+	for _, r := range k {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		dst = utf8.AppendRune(dst, r)
+	}
+	return dst
+}
+
+// appendLogfmtString appends s, quoting it when it is empty or contains
+// spaces, '=', quotes or non-printable characters.
+func appendLogfmtString(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, `""`...)
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return appendJSONString(dst, s)
+		}
+	}
+	return append(dst, s...)
+}
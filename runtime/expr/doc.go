@@ -0,0 +1,55 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package expr implements a small, safe expression language evaluated
+// against records. Expressions are compiled once into a Program and then
+// evaluated per record without reflection or allocation for the common
+// cases; they cannot loop, call functions or modify the record.
+//
+// Example:
+//
+//	level >= warn && ctx.component == "auth" && fields.status >= 500 && msg matches "timeout"
+//
+// Operands:
+//
+//   - level: the record level; compared with the bare level names
+//     trace, debug, info, warn, error and fatal, with level name strings
+//     or with numbers;
+//   - msg: the message; err: the error text ("" without error);
+//   - ctx.<name>: a Pack attribute by canonical field name or custom
+//     attribute name (see context.ResolveAttr); "operation" and
+//     "instance" are accepted for "op" and "instance_id", and other
+//     names are rejected at compile time;
+//   - fields.<key>: the value of the last field with that key. A dotted
+//     key that does not exist as such is resolved through nested maps
+//     (fields.http.status reads key "status" of the map in field "http").
+//     Keys that are not identifiers are written fields["key"] (also
+//     ctx["name"]);
+//   - literals: "strings" (Go escapes), numbers, true, false and null.
+//
+// Operators, by increasing precedence: ||, &&, ! and the comparisons
+// ==, !=, <, <=, >, >=, matches (regular expression given as a string
+// literal), contains (substring) and in [literal, ...]. Parentheses group.
+//
+// Missing values (an absent field, an unset attribute) are null: they are
+// equal only to null, and every ordering, matches or contains on them is
+// false. Values of different types are never equal and never ordered;
+// numbers of all Go numeric types compare as float64.
+//
+// Type errors that can be detected at compile time (msg > 5, an unknown
+// level name, a malformed regular expression) are reported by Compile,
+// together with syntax errors, as *Error values carrying the column.
+package expr
@@ -0,0 +1,58 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package expr
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSyntax is wrapped by errors about malformed expressions.
+	ErrSyntax = errors.New("dlog: expression syntax error")
+
+	// ErrType is wrapped by errors about ill-typed expressions.
+	ErrType = errors.New("dlog: expression type error")
+)
+
+// Error describes a compile error at a position of the source.
+type Error struct {
+	// Kind is ErrSyntax or ErrType.
+	Kind error
+	// Source is the expression being compiled.
+	Source string
+	// Pos is the byte offset of the error in Source.
+	Pos int
+	// Msg describes the problem.
+	Msg string
+}
+
+// Error returns e.g. `dlog: expression type error at column 7: ... in "msg > 5"`.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v at column %d: %s in %q", e.Kind, e.Pos+1, e.Msg, e.Source)
+}
+
+// Unwrap returns Kind.
+func (e *Error) Unwrap() error { return e.Kind }
+
+func syntaxError(src string, pos int, format string, args ...any) error {
+	return &Error{Kind: ErrSyntax, Source: src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func typeError(src string, pos int, format string, args ...any) error {
+	return &Error{Kind: ErrType, Source: src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,559 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package expr
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// Program is a compiled boolean expression. It is immutable and safe for
+// concurrent use.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses and type-checks src, which must be a boolean expression.
+func Compile(src string) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, syntaxError(src, t.pos, "unexpected %s", describe(t))
+	}
+	if root.t != tBool && root.t != tAny {
+		return nil, typeError(src, root.pos, "expression must be boolean, got %s", root.t)
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+// MustCompile is like Compile but panics on error. It is intended for
+// expressions that are constants of the program.
+func MustCompile(src string) *Program {
+	p, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Match evaluates the program against r.
+func (p *Program) Match(r record.Record) bool {
+	// The compiled closures take the record by pointer; evaluating a pooled
+	// copy keeps r itself on the caller's stack.
+	rp := records.Get().(*record.Record)
+	*rp = r
+	ok := p.root.eval(rp).truthy()
+	*rp = record.Record{}
+	records.Put(rp)
+	return ok
+}
+
+var records = sync.Pool{New: func() any { return new(record.Record) }}
+
+// String returns the source of the program.
+func (p *Program) String() string { return p.src }
+
+// node is a compiled, type-checked sub-expression.
+type node struct {
+	t    typ
+	pos  int
+	eval func(r *record.Record) value
+	// lit is set for literals, whose value is known at compile time.
+	lit *value
+}
+
+func literal(v value, pos int) node {
+	return node{t: v.t, pos: pos, eval: func(*record.Record) value { return v }, lit: &v}
+}
+
+// levelNames are the bare identifiers denoting level literals.
+var levelNames = map[string]level.Level{
+	"trace": level.Trace, "debug": level.Debug, "info": level.Info,
+	"warn": level.Warn, "error": level.Error, "fatal": level.Fatal,
+}
+
+// packStrings reads the fixed Pack attributes without boxing them, which
+// Pack.Lookup would do.
+var packStrings = map[string]func(p *dlogctx.Pack) string{
+	fields.CorrelationID: func(p *dlogctx.Pack) string { return p.CorrelationID },
+	fields.TraceID:       func(p *dlogctx.Pack) string { return p.TraceID },
+	fields.SpanID:        func(p *dlogctx.Pack) string { return p.SpanID },
+	fields.Service:       func(p *dlogctx.Pack) string { return p.Service },
+	fields.Version:       func(p *dlogctx.Pack) string { return p.Version },
+	fields.Env:           func(p *dlogctx.Pack) string { return p.Env },
+	fields.NodeID:        func(p *dlogctx.Pack) string { return p.NodeID },
+	fields.InstanceID:    func(p *dlogctx.Pack) string { return p.Instance },
+	fields.Region:        func(p *dlogctx.Pack) string { return p.Region },
+	fields.Component:     func(p *dlogctx.Pack) string { return p.Component },
+	fields.Subsystem:     func(p *dlogctx.Pack) string { return p.Subsystem },
+	fields.Operation:     func(p *dlogctx.Pack) string { return p.Operation },
+}
+
+// parser is a recursive-descent parser that type-checks and compiles as
+// it goes.
+type parser struct {
+	src  string
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// isOp reports whether the next token is the operator op.
+func (p *parser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == op
+}
+
+// isWord reports whether the next token is the keyword w.
+func (p *parser) isWord(w string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == w
+}
+
+func (p *parser) expectOp(op string) (token, error) {
+	t := p.next()
+	if t.kind != tokOp || t.text != op {
+		return t, syntaxError(p.src, t.pos, "expected %q, found %s", op, describe(t))
+	}
+	return t, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return node{}, err
+	}
+	for p.isOp("||") {
+		op := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return node{}, err
+		}
+		if err := p.checkBool(op, left, right); err != nil {
+			return node{}, err
+		}
+		l, r := left.eval, right.eval
+		left = node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+			return boolValue(l(rec).truthy() || r(rec).truthy())
+		}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return node{}, err
+	}
+	for p.isOp("&&") {
+		op := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return node{}, err
+		}
+		if err := p.checkBool(op, left, right); err != nil {
+			return node{}, err
+		}
+		l, r := left.eval, right.eval
+		left = node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+			return boolValue(l(rec).truthy() && r(rec).truthy())
+		}}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if !p.isOp("!") {
+		return p.parseCmp()
+	}
+	op := p.next()
+	x, err := p.parseNot()
+	if err != nil {
+		return node{}, err
+	}
+	if err := p.checkBool(op, x); err != nil {
+		return node{}, err
+	}
+	e := x.eval
+	return node{t: tBool, pos: op.pos, eval: func(rec *record.Record) value {
+		return boolValue(!e(rec).truthy())
+	}}, nil
+}
+
+// checkBool verifies that the operands of a logical operator are boolean.
+func (p *parser) checkBool(op token, ns ...node) error {
+	for _, n := range ns {
+		if n.t != tBool && n.t != tAny {
+			return typeError(p.src, n.pos, "operand of %s must be boolean, got %s", op.text, n.t)
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseCmp() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return node{}, err
+	}
+	t := p.peek()
+	switch {
+	case t.kind == tokOp && (t.text == "==" || t.text == "!=" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return node{}, err
+		}
+		return p.comparison(t, left, right)
+	case p.isWord("matches"):
+		p.next()
+		return p.matches(t, left)
+	case p.isWord("contains"):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return node{}, err
+		}
+		for _, n := range []node{left, right} {
+			if n.t != tStr && n.t != tAny {
+				return node{}, typeError(p.src, n.pos, "operand of contains must be a string, got %s", n.t)
+			}
+		}
+		l, r := left.eval, right.eval
+		return node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+			a, b := l(rec), r(rec)
+			return boolValue(a.t == tStr && b.t == tStr && strings.Contains(a.s, b.s))
+		}}, nil
+	case p.isWord("in"):
+		p.next()
+		return p.in(left)
+	}
+	return left, nil
+}
+
+// comparison compiles left op right.
+func (p *parser) comparison(op token, left, right node) (node, error) {
+	var err error
+	if left, right, err = p.levelOperands(left, right); err != nil {
+		return node{}, err
+	}
+	ordered := op.text != "==" && op.text != "!="
+	if left.t != tAny && right.t != tAny {
+		compatible := left.t == right.t ||
+			(left.t == tLevel && right.t == tNum) || (left.t == tNum && right.t == tLevel) ||
+			(!ordered && (left.t == tNull || right.t == tNull))
+		if !compatible {
+			return node{}, typeError(p.src, op.pos, "cannot compare %s %s %s", left.t, op.text, right.t)
+		}
+		if ordered && (left.t == tBool || left.t == tNull) {
+			return node{}, typeError(p.src, op.pos, "%s values are not ordered", left.t)
+		}
+	}
+
+	l, r := left.eval, right.eval
+	var f func(a, b value) bool
+	switch op.text {
+	case "==":
+		f = equal
+	case "!=":
+		f = func(a, b value) bool { return !equal(a, b) }
+	case "<":
+		f = func(a, b value) bool { c, ok := compare(a, b); return ok && c < 0 }
+	case "<=":
+		f = func(a, b value) bool { c, ok := compare(a, b); return ok && c <= 0 }
+	case ">":
+		f = func(a, b value) bool { c, ok := compare(a, b); return ok && c > 0 }
+	default:
+		f = func(a, b value) bool { c, ok := compare(a, b); return ok && c >= 0 }
+	}
+	return node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+		return boolValue(f(l(rec), r(rec)))
+	}}, nil
+}
+
+// levelOperands converts a string literal compared with a level into a
+// level literal, rejecting unknown level names.
+func (p *parser) levelOperands(left, right node) (node, node, error) {
+	conv := func(n node) (node, error) {
+		if n.lit == nil || n.t != tStr {
+			return n, nil
+		}
+		l, err := level.ParseLevel(n.lit.s)
+		if err != nil {
+			return n, typeError(p.src, n.pos, "unknown level %q", n.lit.s)
+		}
+		return literal(value{t: tLevel, n: float64(l)}, n.pos), nil
+	}
+	var err error
+	switch {
+	case left.t == tLevel:
+		right, err = conv(right)
+	case right.t == tLevel:
+		left, err = conv(left)
+	}
+	return left, right, err
+}
+
+// matches compiles left matches "regexp".
+func (p *parser) matches(op token, left node) (node, error) {
+	t := p.next()
+	if t.kind != tokString {
+		return node{}, syntaxError(p.src, t.pos, "matches expects a string literal, found %s", describe(t))
+	}
+	re, err := regexp.Compile(t.text)
+	if err != nil {
+		return node{}, typeError(p.src, t.pos, "invalid regular expression: %v", err)
+	}
+	if left.t != tStr && left.t != tAny {
+		return node{}, typeError(p.src, op.pos, "operand of matches must be a string, got %s", left.t)
+	}
+	l := left.eval
+	return node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+		v := l(rec)
+		return boolValue(v.t == tStr && re.MatchString(v.s))
+	}}, nil
+}
+
+// in compiles left in [literal, ...].
+func (p *parser) in(left node) (node, error) {
+	if _, err := p.expectOp("["); err != nil {
+		return node{}, err
+	}
+	var set []value
+	for !p.isOp("]") {
+		if len(set) > 0 {
+			if _, err := p.expectOp(","); err != nil {
+				return node{}, err
+			}
+		}
+		n, err := p.parseOperand()
+		if err != nil {
+			return node{}, err
+		}
+		if n.lit == nil {
+			return node{}, syntaxError(p.src, n.pos, "in expects a list of literals")
+		}
+		if left.t == tLevel {
+			if _, n, err = p.levelOperands(left, n); err != nil {
+				return node{}, err
+			}
+		}
+		set = append(set, *n.lit)
+	}
+	p.next()
+	l := left.eval
+	return node{t: tBool, pos: left.pos, eval: func(rec *record.Record) value {
+		v := l(rec)
+		for _, s := range set {
+			if equal(v, s) {
+				return trueValue
+			}
+		}
+		return boolValue(false)
+	}}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokString:
+		return literal(value{t: tStr, s: t.text}, t.pos), nil
+	case tokNumber:
+		return literal(value{t: tNum, n: t.num}, t.pos), nil
+	case tokOp:
+		if t.text == "(" {
+			n, err := p.parseOr()
+			if err != nil {
+				return node{}, err
+			}
+			if _, err := p.expectOp(")"); err != nil {
+				return node{}, err
+			}
+			return n, nil
+		}
+	case tokIdent:
+		return p.ident(t)
+	}
+	return node{}, syntaxError(p.src, t.pos, "expected operand, found %s", describe(t))
+}
+
+// ident compiles identifiers: literals, record attributes and paths.
+func (p *parser) ident(t token) (node, error) {
+	switch t.text {
+	case "true":
+		return literal(trueValue, t.pos), nil
+	case "false":
+		return literal(boolValue(false), t.pos), nil
+	case "null":
+		return literal(null, t.pos), nil
+	case "level":
+		return node{t: tLevel, pos: t.pos, eval: func(r *record.Record) value {
+			return value{t: tLevel, n: float64(r.Level)}
+		}}, nil
+	case "msg":
+		return node{t: tStr, pos: t.pos, eval: func(r *record.Record) value {
+			return value{t: tStr, s: r.Message}
+		}}, nil
+	case "err":
+		return node{t: tStr, pos: t.pos, eval: func(r *record.Record) value {
+			if r.Err == nil {
+				return value{t: tStr}
+			}
+			return value{t: tStr, s: r.Err.Error()}
+		}}, nil
+	case "ctx":
+		name, err := p.path(false)
+		if err != nil {
+			return node{}, err
+		}
+		canon, ok := dlogctx.ResolveAttr(name)
+		if !ok {
+			return node{}, syntaxError(p.src, t.pos, "unknown context attribute %q", name)
+		}
+		name = canon
+		if get, ok := packStrings[name]; ok {
+			return node{t: tAny, pos: t.pos, eval: func(r *record.Record) value {
+				if s := get(&r.Ctx); s != "" {
+					return value{t: tStr, s: s}
+				}
+				return null
+			}}, nil
+		}
+		return node{t: tAny, pos: t.pos, eval: func(r *record.Record) value {
+			v, ok := r.Ctx.Lookup(name)
+			if !ok {
+				return null
+			}
+			return valueOf(v)
+		}}, nil
+	case "fields":
+		key, err := p.path(true)
+		if err != nil {
+			return node{}, err
+		}
+		parts := strings.Split(key, ".")
+		return node{t: tAny, pos: t.pos, eval: func(r *record.Record) value {
+			v, ok := lookupField(r.Fields, key, parts)
+			if !ok {
+				return null
+			}
+			return valueOf(v)
+		}}, nil
+	}
+	if l, ok := levelNames[t.text]; ok {
+		return literal(value{t: tLevel, n: float64(l)}, t.pos), nil
+	}
+	return node{}, syntaxError(p.src, t.pos, "unknown identifier %q", t.text)
+}
+
+// path parses the selector after ctx or fields: .name, ["name"] or, if
+// dotted is set, .name.name... joined with dots.
+func (p *parser) path(dotted bool) (string, error) {
+	if p.isOp("[") {
+		p.next()
+		t := p.next()
+		if t.kind != tokString {
+			return "", syntaxError(p.src, t.pos, "expected string key, found %s", describe(t))
+		}
+		if _, err := p.expectOp("]"); err != nil {
+			return "", err
+		}
+		return t.text, nil
+	}
+	if _, err := p.expectOp("."); err != nil {
+		return "", err
+	}
+	var parts []string
+	for {
+		t := p.next()
+		if t.kind != tokIdent {
+			return "", syntaxError(p.src, t.pos, "expected name, found %s", describe(t))
+		}
+		parts = append(parts, t.text)
+		if !dotted || !p.isOp(".") {
+			return strings.Join(parts, "."), nil
+		}
+		p.next()
+	}
+}
+
+// lookupField returns the value of the last field named key or, failing
+// that, the value reached by following parts through nested maps.
+func lookupField(fs []field.Field, key string, parts []string) (any, bool) {
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key == key {
+			return fs[i].Value, true
+		}
+	}
+	if len(parts) < 2 {
+		return nil, false
+	}
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key != parts[0] {
+			continue
+		}
+		v := fs[i].Value
+		for _, part := range parts[1:] {
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = m[part]; !ok {
+				return nil, false
+			}
+		}
+		return v, true
+	}
+	return nil, false
+}
+
+// describe renders a token for error messages.
+func describe(t token) string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string " + `"` + t.text + `"`
+	case tokNumber:
+		return "number " + t.text
+	default:
+		return "\"" + t.text + "\""
+	}
+}
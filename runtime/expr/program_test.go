@@ -0,0 +1,170 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// testRecord is the record the Match tests are evaluated against.
+var testRecord = record.Record{
+	Level:   level.Warn,
+	Message: "upstream timeout",
+	Err:     errors.New("dial tcp: i/o timeout"),
+	Ctx:     dlogctx.Pack{Service: "api", Component: "auth", Operation: "login"},
+	Fields: []field.Field{
+		field.New("status", 503),
+		field.New("ratio", 0.25),
+		field.New("ok", false),
+		field.New("user", "ann"),
+		field.New("http", map[string]any{"method": "GET", "req": map[string]any{"bytes": 512}}),
+		field.New("http.method", "POST"),
+		field.New("size", json.Number("2048")),
+		field.New("odd key", "x"),
+		field.New("status", 504),
+	},
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		// Operands.
+		{`msg == "upstream timeout"`, true},
+		{`err contains "i/o"`, true},
+		{`ctx.service == "api"`, true},
+		{`ctx.operation == "login" && ctx.op == "login"`, true},
+		{`ctx["component"] == "auth"`, true},
+		{`fields.user == "ann"`, true},
+		{`fields["odd key"] == "x"`, true},
+		{`fields.status == 504`, true}, // the last field wins
+		{`fields.ratio < 0.5`, true},
+		{`fields.ok == false`, true},
+		{`fields.size >= 2048`, true}, // json.Number is a number
+		{`msg matches "^up.*out$"`, true},
+		{`msg matches "^timeout"`, false},
+
+		// Nested lookups: an existing dotted key wins over the path.
+		{`fields.http.method == "POST"`, true},
+		{`fields.http.req.bytes == 512`, true},
+		{`fields.http.req.missing == null`, true},
+		{`fields.user.name == null`, true},
+
+		// Levels: bare names, strings and numbers.
+		{`level >= warn`, true},
+		{`level > warn`, false},
+		{`level == "warn"`, true},
+		{`level == "WARN"`, true},
+		{`"error" > level`, true},
+		{`level >= 3`, true},
+		{`level in [warn, error]`, true},
+		{`level in ["debug", "info"]`, false},
+
+		// in.
+		{`fields.status in [500, 503, 504]`, true},
+		{`fields.user in ["bob", "eve"]`, false},
+		{`fields.missing in [null]`, true},
+
+		// Null semantics.
+		{`fields.missing == null`, true},
+		{`fields.missing != null`, false},
+		{`fields.missing == 0`, false},
+		{`fields.missing != "x"`, true},
+		{`fields.missing > 0`, false},
+		{`fields.missing <= 0`, false},
+		{`!(fields.missing > 0)`, true},
+		{`fields.missing matches ".*"`, false},
+		{`fields.missing contains ""`, false},
+		{`ctx.region == null`, true},
+		{`ctx.tenant_id == null`, true},
+
+		// Values of different types are neither equal nor ordered.
+		{`fields.user == 1`, false},
+		{`fields.user < 1`, false},
+		{`fields.status == "504"`, false},
+
+		// Precedence: ! over comparisons, && over ||.
+		{`true || false && false`, true},
+		{`(true || false) && false`, false},
+		{`!false && false`, false},
+		{`!(false && false)`, true},
+		{`false || !fields.ok`, true},
+		{`fields.status > 500 && fields.user == "ann" || level < debug`, true},
+		{`level < debug || fields.status > 500 && fields.user == "bob"`, false},
+	}
+	for _, tt := range tests {
+		p, err := Compile(tt.src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tt.src, err)
+			continue
+		}
+		if got := p.Match(testRecord); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		kind   error
+		column int
+	}{
+		{`msg == `, ErrSyntax, 8},
+		{`msg == "x`, ErrSyntax, 8},
+		{`(level > warn`, ErrSyntax, 14},
+		{`level >> warn`, ErrSyntax, 8},
+		{`fields.a in [fields.b]`, ErrSyntax, 14},
+		{`msg matches fields.re`, ErrSyntax, 13},
+		{`nope == 1`, ErrSyntax, 1},
+		{`ctx.nope == "x"`, ErrSyntax, 1},
+		{`msg > 5`, ErrType, 5},
+		{`level == "loud"`, ErrType, 10},
+		{`level in [warn, "loud"]`, ErrType, 17},
+		{`msg matches "("`, ErrType, 13},
+		{`true < false`, ErrType, 6},
+		{`msg && true`, ErrType, 1},
+		{`!msg`, ErrType, 2},
+		{`level contains "w"`, ErrType, 1},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src)
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Errorf("Compile(%q) = %v, want an *Error", tt.src, err)
+			continue
+		}
+		if !errors.Is(err, tt.kind) || e.Pos+1 != tt.column {
+			t.Errorf("Compile(%q) = %v, want %v at column %d", tt.src, err, tt.kind, tt.column)
+		}
+	}
+}
+
+func TestMatchDoesNotAllocate(t *testing.T) {
+	p := MustCompile(`level >= warn && ctx.component == "auth" && fields.status >= 500 && msg matches "timeout"`)
+	if n := testing.AllocsPerRun(100, func() { p.Match(testRecord) }); n != 0 {
+		t.Errorf("Match allocates %v times per run", n)
+	}
+}
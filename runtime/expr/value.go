@@ -0,0 +1,181 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"dirpx.dev/dlog/apis/level"
+)
+
+// typ is the static or dynamic type of a value.
+type typ uint8
+
+const (
+	tNull typ = iota
+	tBool
+	tNum
+	tStr
+	tLevel
+	// tAny is the static type of operands known only at run time.
+	tAny
+)
+
+func (t typ) String() string {
+	switch t {
+	case tNull:
+		return "null"
+	case tBool:
+		return "bool"
+	case tNum:
+		return "number"
+	case tStr:
+		return "string"
+	case tLevel:
+		return "level"
+	default:
+		return "any"
+	}
+}
+
+// value is a dynamically typed value. Booleans and levels are stored in n.
+type value struct {
+	t typ
+	s string
+	n float64
+}
+
+var (
+	null      = value{}
+	trueValue = value{t: tBool, n: 1}
+)
+
+func boolValue(b bool) value {
+	if b {
+		return trueValue
+	}
+	return value{t: tBool}
+}
+
+// truthy reports whether v is the boolean true.
+func (v value) truthy() bool { return v.t == tBool && v.n != 0 }
+
+// valueOf converts a Go value found in a record into a value.
+func valueOf(x any) value {
+	switch v := x.(type) {
+	case nil:
+		return null
+	case string:
+		return value{t: tStr, s: v}
+	case bool:
+		return boolValue(v)
+	case int:
+		return value{t: tNum, n: float64(v)}
+	case int8:
+		return value{t: tNum, n: float64(v)}
+	case int16:
+		return value{t: tNum, n: float64(v)}
+	case int32:
+		return value{t: tNum, n: float64(v)}
+	case int64:
+		return value{t: tNum, n: float64(v)}
+	case uint:
+		return value{t: tNum, n: float64(v)}
+	case uint8:
+		return value{t: tNum, n: float64(v)}
+	case uint16:
+		return value{t: tNum, n: float64(v)}
+	case uint32:
+		return value{t: tNum, n: float64(v)}
+	case uint64:
+		return value{t: tNum, n: float64(v)}
+	case float32:
+		return value{t: tNum, n: float64(v)}
+	case float64:
+		return value{t: tNum, n: v}
+	case level.Level:
+		return value{t: tLevel, n: float64(v)}
+	case time.Duration:
+		return value{t: tNum, n: v.Seconds()}
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return value{t: tNum, n: f}
+		}
+		return value{t: tStr, s: v.String()}
+	case error:
+		return value{t: tStr, s: v.Error()}
+	case fmt.Stringer:
+		return value{t: tStr, s: v.String()}
+	default:
+		return value{t: tStr, s: fmt.Sprint(v)}
+	}
+}
+
+// equal reports whether a and b have the same type and value. A level
+// equals a number with the same ordinal.
+func equal(a, b value) bool {
+	if a.t == tLevel && b.t == tNum || a.t == tNum && b.t == tLevel {
+		return a.n == b.n
+	}
+	if a.t != b.t {
+		return false
+	}
+	switch a.t {
+	case tStr:
+		return a.s == b.s
+	case tNull:
+		return true
+	default:
+		return a.n == b.n
+	}
+}
+
+// compare orders a and b; ok is false if they are not ordered (different
+// types, null or booleans).
+func compare(a, b value) (c int, ok bool) {
+	at, bt := a.t, b.t
+	if at == tLevel {
+		at = tNum
+	}
+	if bt == tLevel {
+		bt = tNum
+	}
+	if at != bt {
+		return 0, false
+	}
+	switch at {
+	case tNum:
+		switch {
+		case a.n < b.n:
+			return -1, true
+		case a.n > b.n:
+			return 1, true
+		}
+		return 0, true
+	case tStr:
+		switch {
+		case a.s < b.s:
+			return -1, true
+		case a.s > b.s:
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,31 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package httpctx propagates the dlog context Pack over HTTP.
+//
+// On the server side, Middleware reads the W3C "traceparent" header into
+// Pack.TraceID/SpanID and a configurable correlation header (for example
+// "X-Correlation-ID") into Pack.CorrelationID, generating a new correlation
+// ID when the caller did not send one. The resulting Pack is stored in the
+// request context with context.WithPack, where Extractor (or
+// context.FromContext) picks it up, and the correlation ID is echoed back
+// in the response headers.
+//
+// On the client side, Transport is an http.RoundTripper that writes the
+// same headers from the Pack found in the outgoing request context, so
+// correlation survives service boundaries. The traceparent it sends keeps
+// the trace ID but names a new child span as the parent of the callee.
+package httpctx
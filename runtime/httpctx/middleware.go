@@ -0,0 +1,141 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+)
+
+const (
+	// DefaultCorrelationHeader is the correlation header used when
+	// Options.CorrelationHeader is empty.
+	DefaultCorrelationHeader = "X-Correlation-ID"
+
+	// maxCorrelationIDLen bounds accepted inbound correlation IDs so that
+	// clients cannot inject arbitrarily large values into every log line.
+	maxCorrelationIDLen = 128
+)
+
+// Options configures Middleware and Transport.
+type Options struct {
+	// CorrelationHeader is the header carrying the correlation ID.
+	// If empty, DefaultCorrelationHeader is used.
+	CorrelationHeader string
+
+	// Generate returns a new correlation ID for requests that arrive
+	// without a valid one. If nil, a random 128-bit hex ID is generated.
+	Generate func() string
+}
+
+// correlationHeader returns the configured header or the default.
+func (o Options) correlationHeader() string {
+	if o.CorrelationHeader != "" {
+		return o.CorrelationHeader
+	}
+	return DefaultCorrelationHeader
+}
+
+// generate returns a new correlation ID.
+func (o Options) generate() string {
+	if o.Generate != nil {
+		return o.Generate()
+	}
+	return NewID()
+}
+
+// flagsKey is the context key for the inbound trace-flags, which
+// Transport forwards unchanged.
+type flagsKey struct{}
+
+// Middleware returns an http.Handler that builds a Pack from the request
+// headers, stores it in the request context via context.WithPack and
+// echoes the correlation ID in the response headers before calling next.
+//
+// A Pack already present in the context (e.g. set by an outer middleware)
+// is preserved; values parsed from the request override it.
+// A nil opts is equivalent to the zero Options.
+func Middleware(next http.Handler, opts *Options) http.Handler {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	header := o.correlationHeader()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p dlogctx.Pack
+		ctx := r.Context()
+
+		if tp, err := ParseTraceparent(r.Header.Get(TraceparentHeader)); err == nil {
+			p.TraceID = tp.TraceID
+			p.SpanID = tp.SpanID
+			ctx = context.WithValue(ctx, flagsKey{}, tp.Flags)
+		}
+
+		id := r.Header.Get(header)
+		if !validCorrelationID(id) {
+			id = o.generate()
+		}
+		p.CorrelationID = id
+		w.Header().Set(header, id)
+
+		ctx = dlogctx.WithPack(ctx, p)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Extractor returns an Extractor that yields the Pack stored by Middleware.
+// It is equivalent to context.FromContext and can be combined with other
+// extractors via context.Chain.
+func Extractor() dlogctx.Extractor {
+	return dlogctx.FromContext()
+}
+
+// NewID returns a random 128-bit identifier encoded as 32 hex characters.
+func NewID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// NewSpanID returns a random, non-zero 64-bit span identifier encoded as
+// 16 hex characters.
+func NewSpanID() string {
+	var b [8]byte
+	for b == [8]byte{} {
+		_, _ = rand.Read(b[:])
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// validCorrelationID reports whether id is non-empty, reasonably short and
+// consists of printable ASCII only, which keeps log lines injection-free.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,100 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpctx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TraceparentHeader is the W3C Trace Context header name.
+const TraceparentHeader = "traceparent"
+
+var (
+	// ErrTraceparentInvalid is returned when a traceparent header value
+	// does not follow the W3C Trace Context format.
+	ErrTraceparentInvalid = errors.New("dlog: invalid traceparent")
+)
+
+// Traceparent is a parsed W3C traceparent header value.
+type Traceparent struct {
+	// TraceID is the 32 lowercase hex character trace identifier.
+	TraceID string
+
+	// SpanID is the 16 lowercase hex character parent span identifier.
+	SpanID string
+
+	// Flags is the 2 lowercase hex character trace-flags field
+	// ("01" means sampled).
+	Flags string
+}
+
+// ParseTraceparent parses a traceparent header value of the form
+//
+//	version "-" trace-id "-" parent-id "-" trace-flags
+//
+// Version "ff" and all-zero trace or parent IDs are rejected. Higher
+// versions are accepted as long as their first four parts are valid,
+// as required by the specification for forward compatibility.
+func ParseTraceparent(s string) (Traceparent, error) {
+	s = strings.TrimSpace(s)
+	parts := strings.SplitN(s, "-", 5)
+	if len(parts) < 4 {
+		return Traceparent{}, fmt.Errorf("%w: %q", ErrTraceparentInvalid, s)
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	switch {
+	case !isHex(version, 2) || version == "ff":
+	case version == "00" && len(parts) != 4:
+	case !isHex(traceID, 32) || isZero(traceID):
+	case !isHex(spanID, 16) || isZero(spanID):
+	case !isHex(flags, 2):
+	default:
+		return Traceparent{TraceID: traceID, SpanID: spanID, Flags: flags}, nil
+	}
+	return Traceparent{}, fmt.Errorf("%w: %q", ErrTraceparentInvalid, s)
+}
+
+// String formats tp as a version "00" traceparent header value.
+// Empty Flags are rendered as "01" (sampled).
+func (tp Traceparent) String() string {
+	flags := tp.Flags
+	if flags == "" {
+		flags = "01"
+	}
+	return "00-" + tp.TraceID + "-" + tp.SpanID + "-" + flags
+}
+
+// isHex reports whether s is exactly n lowercase hex characters.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isZero reports whether s consists only of '0' characters.
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpctx
+
+import (
+	"net/http"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+)
+
+// Transport is an http.RoundTripper that injects the traceparent and
+// correlation headers from the Pack carried by the request context
+// (see context.WithPack), whether it was set by Middleware or by the
+// application itself.
+//
+// The outgoing traceparent keeps the trace ID and flags of the Pack but
+// carries a new span ID from NewSpanID: per W3C Trace Context, its
+// parent-id names the span making the call, a child of the Pack's span,
+// not the inbound caller's span.
+//
+// Headers already present on the outgoing request are left untouched,
+// so callers (or a tracing library) can still set them explicitly.
+type Transport struct {
+	// Base is the underlying RoundTripper. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Options selects the correlation header; Generate is not used.
+	Options Options
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// RoundTrip implements http.RoundTripper.
+// The request is cloned before headers are added, as required by the
+// http.RoundTripper contract.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	p := dlogctx.PackFrom(r.Context())
+	flags, _ := r.Context().Value(flagsKey{}).(string)
+	header := t.Options.correlationHeader()
+
+	var set [][2]string
+	if p.TraceID != "" && r.Header.Get(TraceparentHeader) == "" {
+		tp := Traceparent{TraceID: p.TraceID, SpanID: NewSpanID(), Flags: flags}
+		set = append(set, [2]string{TraceparentHeader, tp.String()})
+	}
+	if p.CorrelationID != "" && r.Header.Get(header) == "" {
+		set = append(set, [2]string{header, p.CorrelationID})
+	}
+	if len(set) == 0 {
+		return base.RoundTrip(r)
+	}
+
+	out := r.Clone(r.Context())
+	for _, kv := range set {
+		out.Header.Set(kv[0], kv[1])
+	}
+	return base.RoundTrip(out)
+}
@@ -0,0 +1,170 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package spec contains helpers shared by runtime plugin builders for
+// working with plugin.Specification: decoding the opaque Config payload,
+// resolving defaults, and following configuration changes published by
+// providers.
+package spec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/provider"
+)
+
+// Decode stores the plugin Config payload in out, which must be a non-nil
+// pointer to the plugin's config type.
+//
+// Accepted payloads:
+//   - nil: out is left untouched (defaults apply);
+//   - a value or pointer of out's element type: copied directly;
+//   - []byte / json.RawMessage: decoded as JSON;
+//   - anything else (typically map[string]any from a JSON/YAML provider):
+//     round-tripped through JSON.
+//
+// Unknown keys are rejected so that typos in configs surface at Build time.
+func Decode(in any, out any) error {
+	if in == nil {
+		return nil
+	}
+	ov := reflect.ValueOf(out)
+	if ov.Kind() != reflect.Pointer || ov.IsNil() {
+		return fmt.Errorf("dlog: config target must be a non-nil pointer, got %T", out)
+	}
+	elem := ov.Elem()
+
+	iv := reflect.ValueOf(in)
+	switch {
+	case iv.Type() == elem.Type():
+		elem.Set(iv)
+		return nil
+	case iv.Kind() == reflect.Pointer && iv.Type().Elem() == elem.Type():
+		if !iv.IsNil() {
+			elem.Set(iv.Elem())
+		}
+		return nil
+	}
+
+	var raw []byte
+	switch v := in.(type) {
+	case json.RawMessage:
+		raw = v
+	case []byte:
+		raw = v
+	default:
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("dlog: encode config: %w", err)
+		}
+		raw = b
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("dlog: decode config: %w", err)
+	}
+	return nil
+}
+
+// Enabled resolves the Enabled pointer, defaulting to true.
+func Enabled(s plugin.Specification) bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// Name returns the diagnostic name of the plugin: Name if set, else Kind.
+func Name(s plugin.Specification) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Kind
+}
+
+// Find looks up the plugin with the given kind and diagnostic name in the
+// Pre and Post lists of the provider specification, then in the Pre lists
+// of its sink bindings in sink name order.
+func Find(ps *provider.Specification, kind, name string) (plugin.Specification, bool) {
+	if ps == nil || ps.Pipeline == nil {
+		return plugin.Specification{}, false
+	}
+	lists := [][]plugin.Specification{ps.Pipeline.Pre, ps.Pipeline.Post}
+	sinks := make([]string, 0, len(ps.Pipeline.Bindings))
+	for sink := range ps.Pipeline.Bindings {
+		sinks = append(sinks, sink)
+	}
+	sort.Strings(sinks)
+	for _, sink := range sinks {
+		lists = append(lists, ps.Pipeline.Bindings[sink].Pre)
+	}
+	for _, list := range lists {
+		for _, s := range list {
+			if s.Kind == kind && Name(s) == name {
+				return s, true
+			}
+		}
+	}
+	return plugin.Specification{}, false
+}
+
+// Watch follows stream and calls apply with the plugin specification
+// identified by kind and name every time a provider publishes it.
+//
+// Changes that do not mention the plugin, deletions and provider errors
+// leave the current configuration in place. Errors returned by apply and
+// provider errors are reported to onError, which may be nil.
+//
+// Watch blocks until ctx is done (returning ctx.Err()) or the stream is
+// closed (returning nil); run it in its own goroutine.
+func Watch(
+	ctx context.Context,
+	stream provider.Stream,
+	kind, name string,
+	apply func(plugin.Specification) error,
+	onError func(error),
+) error {
+	report := func(err error) {
+		if onError != nil && err != nil {
+			onError(err)
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ch, ok := <-stream.Updates():
+			if !ok {
+				return nil
+			}
+			if ch.Reason == provider.ChangeError {
+				report(ch.Err)
+				continue
+			}
+			s, found := Find(ch.Spec, kind, name)
+			if !found {
+				continue
+			}
+			if err := apply(s); err != nil {
+				report(fmt.Errorf("dlog: reload %s %q from %s@%s: %w", kind, name, ch.Source, ch.Version, err))
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/apis/sink"
+	"dirpx.dev/dlog/runtime/encoder"
+)
+
+// target is a sink together with its pipeline.SinkBinding.
+type target struct {
+	sink sink.Sink
+	min  level.Level
+	// enc indexes Router.encoders.
+	enc int
+	// chain runs the binding plugins and ends in deliver; nil without
+	// binding plugins.
+	chain *Chain
+	// encoder is Router.encoders[enc], for chain deliveries.
+	encoder encoder.Encoder
+}
+
+// deliver encodes r and writes it to the sink.
+func (t *target) deliver(ctx context.Context, r record.Record) error {
+	entry, err := t.encoder.Append(nil, r)
+	if err != nil {
+		return fmt.Errorf("dlog: encode %s: %w", t.encoder.Name(), err)
+	}
+	return t.write(ctx, entry)
+}
+
+// write writes an encoded entry to the sink.
+func (t *target) write(ctx context.Context, entry []byte) error {
+	if err := t.sink.Write(ctx, entry); err != nil {
+		return fmt.Errorf("dlog: sink %q: %w", t.sink.Name(), err)
+	}
+	return nil
+}
+
+// routerBuilder resolves the sinks of a specification into targets, each
+// built once.
+type routerBuilder struct {
+	ctx      context.Context
+	ps       pipeline.Specification
+	reg      *Registry
+	opts     *RouterOptions
+	rt       *Router
+	targets  map[string]*target
+	encoders map[string]int
+	plugins  map[string]plugin.Builder
+}
+
+// resolve returns the targets of the named sinks.
+func (b *routerBuilder) resolve(names []string) ([]*target, error) {
+	out := make([]*target, 0, len(names))
+	for _, name := range names {
+		t, err := b.target(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// target returns the target of the named sink, building it on first use.
+func (b *routerBuilder) target(name string) (*target, error) {
+	if t, ok := b.targets[name]; ok {
+		return t, nil
+	}
+	s, ok := b.reg.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (registered: %v)", ErrSinkUnknown, name, b.reg.Names())
+	}
+	t := &target{sink: s, min: level.Trace}
+	bd := b.ps.Bindings[name]
+	if bd.MinLevel != nil {
+		t.min = *bd.MinLevel
+	}
+	var err error
+	if t.enc, err = b.encoder(bd.Encoder); err != nil {
+		return nil, fmt.Errorf("dlog: binding %q: %w", name, err)
+	}
+	t.encoder = b.rt.encoders[t.enc]
+	if len(bd.Pre) > 0 {
+		stages, err := b.stages(name, bd.Pre)
+		if err != nil {
+			return nil, fmt.Errorf("dlog: binding %q: %w", name, err)
+		}
+		t.chain = NewStepChain(stages, t.deliver)
+	}
+	b.targets[name] = t
+	b.rt.targets = append(b.rt.targets, t)
+	return t, nil
+}
+
+// encoder returns the index of the named encoder, adding it on first use.
+// The empty name selects the default encoder.
+func (b *routerBuilder) encoder(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	if i, ok := b.encoders[name]; ok {
+		return i, nil
+	}
+	enc, ok := b.opts.Encoders[name]
+	if !ok {
+		switch {
+		case b.rt.encoders[0].Name() == name:
+			// Share the default encoder rather than encoding twice.
+			b.encoders[name] = 0
+			return 0, nil
+		case name == "json":
+			enc = encoder.NewJSON(nil)
+		case name == "logfmt":
+			enc = encoder.NewLogfmt(nil)
+		default:
+			return 0, fmt.Errorf("%w: %q", ErrEncoderUnknown, name)
+		}
+	}
+	b.rt.encoders = append(b.rt.encoders, enc)
+	b.encoders[name] = len(b.rt.encoders) - 1
+	return len(b.rt.encoders) - 1, nil
+}
+
+// stages builds the binding plugins of sink. Stages implementing
+// stage.SinkScoped are replaced by their variant for the sink.
+func (b *routerBuilder) stages(sink string, specs []plugin.Specification) ([]Step, error) {
+	steps, err := buildSteps(b.ctx, specs, b.plugins)
+	if err != nil {
+		return nil, err
+	}
+	for i := range steps {
+		if sc, ok := steps[i].Stage.(stage.SinkScoped); ok {
+			steps[i].Stage = sc.ForSink(sink)
+		}
+	}
+	return steps, nil
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/runtime/expr"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+var (
+	// ErrPluginUnknown is returned when a specification uses a plugin kind
+	// that has no builder.
+	ErrPluginUnknown = errors.New("dlog: unknown plugin kind")
+
+	// ErrSinkScoped is returned by BuildSteps for a stage with per-sink
+	// settings, which only sink bindings can apply.
+	ErrSinkScoped = errors.New("dlog: per-sink settings outside a sink binding")
+)
+
+// BuildSteps builds the plugins of specs, in order, with the builders of
+// their kinds, and carries their error policies and compiled When
+// conditions into the returned steps.
+// Use the result with NewStepChain.
+//
+// The steps run before any sink is selected, so a stage.SinkScoped stage
+// with per-sink settings is rejected with ErrSinkScoped; configure it in
+// a pipeline.SinkBinding instead.
+func BuildSteps(ctx context.Context, specs []plugin.Specification, builders []plugin.Builder) ([]Step, error) {
+	steps, err := buildSteps(ctx, specs, builderIndex(builders))
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range steps {
+		if sc, ok := st.Stage.(stage.SinkScoped); ok && len(sc.Sinks()) > 0 {
+			return nil, fmt.Errorf("%w: plugin %q has settings for sinks %q", ErrSinkScoped, st.Stage.Name(), sc.Sinks())
+		}
+	}
+	return steps, nil
+}
+
+// builderIndex indexes builders by Kind.
+func builderIndex(builders []plugin.Builder) map[string]plugin.Builder {
+	m := make(map[string]plugin.Builder, len(builders))
+	for _, b := range builders {
+		m[b.Kind()] = b
+	}
+	return m
+}
+
+func buildSteps(ctx context.Context, specs []plugin.Specification, builders map[string]plugin.Builder) ([]Step, error) {
+	out := make([]Step, 0, len(specs))
+	for _, ps := range specs {
+		pb, ok := builders[ps.Kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrPluginUnknown, ps.Kind)
+		}
+		if err := ps.OnError.Validate(); err != nil {
+			return nil, fmt.Errorf("dlog: plugin %q: %w", spec.Name(ps), err)
+		}
+		step := Step{OnError: ps.OnError}
+		if ps.When != "" {
+			prog, err := expr.Compile(ps.When)
+			if err != nil {
+				return nil, fmt.Errorf("dlog: plugin %q: when: %w", spec.Name(ps), err)
+			}
+			step.When = prog
+		}
+		s, err := pb.Build(ctx, ps)
+		if err != nil {
+			return nil, fmt.Errorf("dlog: plugin %q: %w", spec.Name(ps), err)
+		}
+		step.Stage = s
+		out = append(out, step)
+	}
+	return out, nil
+}
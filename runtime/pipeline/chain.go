@@ -0,0 +1,331 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/pipeline"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/expr"
+)
+
+// Handler receives the records that passed every stage of a Chain.
+type Handler func(ctx context.Context, r record.Record) error
+
+// Flusher is implemented by stages that buffer state and want to act on
+// Pipeline.Flush.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+var (
+	// ErrStagePanic is reported for a stage that panicked while
+	// processing a record.
+	ErrStagePanic = errors.New("dlog: stage panicked")
+)
+
+// Step is a stage together with the settings of its plugin.Specification
+// that the Chain applies around it.
+type Step struct {
+	Stage stage.Stage
+
+	// OnError is the error policy of the stage; empty means
+	// plugin.OnErrorFailClosed.
+	OnError plugin.ErrorPolicy
+
+	// When restricts the stage to the records it matches; nil means all
+	// records.
+	When *expr.Program
+}
+
+// StageStats are the error counters of one stage of a Chain.
+type StageStats struct {
+	// Name is the stage name.
+	Name string
+
+	// Errors counts the errors of the stage, recovered panics included.
+	Errors uint64
+
+	// Panics counts the recovered panics of the stage.
+	Panics uint64
+
+	// EmitErrors counts the records released through the stage's
+	// stage.Emitter whose processing by the rest of the chain failed.
+	EmitErrors uint64
+
+	// Fired and Skipped count the records the When condition of the stage
+	// selected and rejected. Both stay zero for unconditional stages.
+	Fired   uint64
+	Skipped uint64
+}
+
+// StepReport tells whether a stage of a Chain would process a record.
+type StepReport struct {
+	// Name is the stage name.
+	Name string
+
+	// Enabled reports whether the stage is enabled.
+	Enabled bool
+
+	// When is the source of the stage condition, or "" if it has none.
+	When string
+
+	// Fires reports whether the stage would process the record: it is
+	// enabled and its condition, if any, matches.
+	Fires bool
+}
+
+// step is a Step with its counters.
+type step struct {
+	Step
+	errors  atomic.Uint64
+	panics  atomic.Uint64
+	emitErr atomic.Uint64
+	fired   atomic.Uint64
+	skipped atomic.Uint64
+}
+
+// Chain executes an ordered list of stages. It is safe for concurrent use
+// as long as its stages are.
+//
+// A stage that panics is recovered; the panic is handled as an error
+// wrapping ErrStagePanic.
+//
+// An error returned with stage.Continue and a non-zero record is partial:
+// the stage processed the record but could not do all of it, such as a
+// transform operation that found a field of the wrong type. The record
+// continues as returned by the stage; the error is counted, added to the
+// fields.PipelineError field under plugin.OnErrorAnnotate and returned
+// by Emit under plugin.OnErrorAbort.
+//
+// Any other error is a stage failure and is handled according to the
+// error policy of the stage's Step:
+//
+//   - plugin.OnErrorFailClosed (default): the record is dropped;
+//   - plugin.OnErrorFailOpen: the record continues as it was before the
+//     stage;
+//   - plugin.OnErrorAbort: the record is dropped and Emit returns the
+//     error immediately;
+//   - plugin.OnErrorAnnotate: the record continues as returned by the
+//     stage (as it was, if the stage returned the zero Record) with the
+//     error added to the fields.PipelineError field; after a panic it is
+//     dropped.
+//
+// Only fail_open lets a record through a stage that panicked.
+//
+// Records released by a stage.Deferrer are processed by the rest of the
+// chain like emitted ones. Their errors are returned to the stage by
+// Emitter.Emit and counted in StageStats.EmitErrors, since stages cannot
+// attribute them to the record they are processing.
+//
+// Stages with a When condition only process the records it matches;
+// the others skip the stage unchanged.
+//
+// Every error is counted; see Stats.
+type Chain struct {
+	steps []step
+	next  Handler
+}
+
+var _ pipeline.Pipeline = (*Chain)(nil)
+
+// NewChain returns a Chain running stages in order, with the default error
+// policy, and passing surviving records to next, which may be nil. Every
+// stage.Deferrer among stages is bound here, so a stage instance must not
+// be shared between chains.
+func NewChain(stages []stage.Stage, next Handler) *Chain {
+	steps := make([]Step, len(stages))
+	for i, s := range stages {
+		steps[i] = Step{Stage: s}
+	}
+	return NewStepChain(steps, next)
+}
+
+// NewStepChain is like NewChain but takes the stages with their settings.
+func NewStepChain(steps []Step, next Handler) *Chain {
+	c := &Chain{steps: make([]step, len(steps)), next: next}
+	for i, s := range steps {
+		c.steps[i].Step = s
+		d, ok := s.Stage.(stage.Deferrer)
+		if !ok {
+			continue
+		}
+		from := i + 1
+		st := &c.steps[i]
+		d.Bind(stage.EmitterFunc(func(ctx context.Context, rs ...record.Record) error {
+			var errs []error
+			for _, r := range rs {
+				if err := c.run(ctx, from, r); err != nil {
+					st.emitErr.Add(1)
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		}))
+	}
+	return c
+}
+
+// Emit runs r through all enabled stages and, unless a stage drops or
+// defers it, through the terminal handler. Stage errors are handled by
+// the stage error policies; only aborting errors and errors of the
+// terminal handler are returned.
+func (c *Chain) Emit(ctx context.Context, r record.Record) error {
+	return c.run(ctx, 0, r)
+}
+
+// Flush calls Flush on every stage implementing Flusher, in order.
+func (c *Chain) Flush(ctx context.Context) error {
+	var errs []error
+	for i := range c.steps {
+		s := c.steps[i].Stage
+		if f, ok := s.(Flusher); ok {
+			if err := f.Flush(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("dlog: flush stage %q: %w", s.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns the error counters of every stage, in chain order.
+func (c *Chain) Stats() []StageStats {
+	out := make([]StageStats, len(c.steps))
+	for i := range c.steps {
+		st := &c.steps[i]
+		out[i] = StageStats{
+			Name:       st.Stage.Name(),
+			Errors:     st.errors.Load(),
+			Panics:     st.panics.Load(),
+			EmitErrors: st.emitErr.Load(),
+			Fired:      st.fired.Load(),
+			Skipped:    st.skipped.Load(),
+		}
+	}
+	return out
+}
+
+// Explain reports, for every stage in order, whether it would process r,
+// without running any stage. It is meant for dry-run tooling; note that
+// every condition is evaluated against r itself, whereas in Emit it sees
+// the record as modified by the previous stages.
+func (c *Chain) Explain(r record.Record) []StepReport {
+	out := make([]StepReport, len(c.steps))
+	for i := range c.steps {
+		st := &c.steps[i]
+		rep := StepReport{Name: st.Stage.Name(), Enabled: st.Stage.Enabled()}
+		rep.Fires = rep.Enabled
+		if st.When != nil {
+			rep.When = st.When.String()
+			rep.Fires = rep.Enabled && st.When.Match(r)
+		}
+		out[i] = rep
+	}
+	return out
+}
+
+// run processes r starting at stage index from.
+func (c *Chain) run(ctx context.Context, from int, r record.Record) error {
+	for i := from; i < len(c.steps); i++ {
+		st := &c.steps[i]
+		s := st.Stage
+		if !s.Enabled() {
+			continue
+		}
+		if st.When != nil {
+			if !st.When.Match(r) {
+				st.skipped.Add(1)
+				continue
+			}
+			st.fired.Add(1)
+		}
+		out, d, panicked, err := st.process(ctx, r)
+		if err != nil {
+			st.errors.Add(1)
+			if panicked {
+				st.panics.Add(1)
+			}
+			partial := !panicked && d == stage.Continue && !out.IsZero()
+			switch {
+			case st.OnError == plugin.OnErrorAbort:
+				return fmt.Errorf("dlog: stage %q: %w", s.Name(), err)
+			case partial && st.OnError == plugin.OnErrorAnnotate:
+				out = annotate(out, fmt.Sprintf("stage %q: %v", s.Name(), err))
+			case partial:
+				// Counted only.
+			case st.OnError == plugin.OnErrorFailOpen:
+				out, d = r, stage.Continue
+			case st.OnError == plugin.OnErrorAnnotate && !panicked:
+				if out.IsZero() {
+					out = r
+				}
+				out = annotate(out, fmt.Sprintf("stage %q: %v", s.Name(), err))
+			default:
+				// fail_closed, and annotate after a panic.
+				return nil
+			}
+		}
+		switch d {
+		case stage.Continue:
+			r = out
+		case stage.Drop, stage.Defer:
+			return nil
+		default:
+			return fmt.Errorf("dlog: stage %q: unknown decision %d", s.Name(), d)
+		}
+	}
+	if c.next != nil {
+		return c.next(ctx, r)
+	}
+	return nil
+}
+
+// process runs the stage, turning a panic into an error wrapping
+// ErrStagePanic.
+func (st *step) process(ctx context.Context, r record.Record) (out record.Record, d stage.Decision, panicked bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			out, d, panicked, err = r, stage.Continue, true, fmt.Errorf("%w: %v", ErrStagePanic, v)
+		}
+	}()
+	out, d, err = st.Stage.Process(ctx, r)
+	return out, d, false, err
+}
+
+// annotate adds note to the fields.PipelineError list of r.
+func annotate(r record.Record, note string) record.Record {
+	for i := len(r.Fields) - 1; i >= 0; i-- {
+		if r.Fields[i].Key != fields.PipelineError {
+			continue
+		}
+		notes, _ := r.Fields[i].Value.([]string)
+		fs := slices.Clone(r.Fields)
+		fs[i].Value = append(slices.Clip(notes), note)
+		r.Fields = fs
+		return r
+	}
+	return r.WithFields(field.New(fields.PipelineError, []string{note}))
+}
@@ -0,0 +1,173 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// stub is a stage whose Process is fn.
+type stub struct {
+	fn func(r record.Record) (record.Record, stage.Decision, error)
+}
+
+func (s stub) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	return s.fn(r)
+}
+func (stub) Name() string  { return "stub" }
+func (stub) Enabled() bool { return true }
+
+var errStub = errors.New("stub error")
+
+// Stage behaviours exercised by TestChainPolicies.
+var (
+	// partial processes the record but reports an error.
+	partial = stub{func(r record.Record) (record.Record, stage.Decision, error) {
+		return r.WithFields(field.New("seen", true)), stage.Continue, errStub
+	}}
+	// failing returns an error and no record.
+	failing = stub{func(record.Record) (record.Record, stage.Decision, error) {
+		return record.Record{}, stage.Continue, errStub
+	}}
+	// dropping returns an error together with Drop.
+	dropping = stub{func(r record.Record) (record.Record, stage.Decision, error) {
+		return r, stage.Drop, errStub
+	}}
+	// panicking panics.
+	panicking = stub{func(record.Record) (record.Record, stage.Decision, error) {
+		panic("boom")
+	}}
+)
+
+func TestChainPolicies(t *testing.T) {
+	type want struct {
+		delivered bool
+		seen      bool // the field added by the stage is present
+		annotated bool
+		err       bool
+	}
+	tests := []struct {
+		name   string
+		stage  stage.Stage
+		policy plugin.ErrorPolicy
+		want   want
+	}{
+		{"partial/default", partial, "", want{delivered: true, seen: true}},
+		{"partial/fail_closed", partial, plugin.OnErrorFailClosed, want{delivered: true, seen: true}},
+		{"partial/fail_open", partial, plugin.OnErrorFailOpen, want{delivered: true, seen: true}},
+		{"partial/annotate", partial, plugin.OnErrorAnnotate, want{delivered: true, seen: true, annotated: true}},
+		{"partial/abort", partial, plugin.OnErrorAbort, want{err: true}},
+
+		{"failing/default", failing, "", want{}},
+		{"failing/fail_closed", failing, plugin.OnErrorFailClosed, want{}},
+		{"failing/fail_open", failing, plugin.OnErrorFailOpen, want{delivered: true}},
+		{"failing/annotate", failing, plugin.OnErrorAnnotate, want{delivered: true, annotated: true}},
+		{"failing/abort", failing, plugin.OnErrorAbort, want{err: true}},
+
+		{"dropping/fail_closed", dropping, plugin.OnErrorFailClosed, want{}},
+		{"dropping/fail_open", dropping, plugin.OnErrorFailOpen, want{delivered: true}},
+		{"dropping/annotate", dropping, plugin.OnErrorAnnotate, want{}},
+		{"dropping/abort", dropping, plugin.OnErrorAbort, want{err: true}},
+
+		{"panicking/fail_closed", panicking, plugin.OnErrorFailClosed, want{}},
+		{"panicking/fail_open", panicking, plugin.OnErrorFailOpen, want{delivered: true}},
+		{"panicking/annotate", panicking, plugin.OnErrorAnnotate, want{}},
+		{"panicking/abort", panicking, plugin.OnErrorAbort, want{err: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []record.Record
+			c := NewStepChain([]Step{{Stage: tt.stage, OnError: tt.policy}}, func(_ context.Context, r record.Record) error {
+				got = append(got, r)
+				return nil
+			})
+			err := c.Emit(context.Background(), record.Record{Message: "m"})
+			if (err != nil) != tt.want.err {
+				t.Fatalf("Emit error = %v, want error %v", err, tt.want.err)
+			}
+			if tt.want.err && !errors.Is(err, errStub) && !errors.Is(err, ErrStagePanic) {
+				t.Errorf("Emit error = %v, want the stage error", err)
+			}
+			if (len(got) == 1) != tt.want.delivered {
+				t.Fatalf("delivered %d records, want delivered %v", len(got), tt.want.delivered)
+			}
+			if st := c.Stats()[0]; st.Errors != 1 {
+				t.Errorf("Stats().Errors = %d, want 1", st.Errors)
+			}
+			if !tt.want.delivered {
+				return
+			}
+			if got[0].Message != "m" {
+				t.Errorf("Message = %q, want %q", got[0].Message, "m")
+			}
+			if _, ok := lookup(got[0], "seen"); ok != tt.want.seen {
+				t.Errorf("stage field present = %v, want %v", ok, tt.want.seen)
+			}
+			if _, ok := lookup(got[0], fields.PipelineError); ok != tt.want.annotated {
+				t.Errorf("%s present = %v, want %v", fields.PipelineError, ok, tt.want.annotated)
+			}
+		})
+	}
+}
+
+// emitting is a stage.Deferrer that releases a copy of every record it
+// processes through its Emitter.
+type emitting struct {
+	stub
+	emit stage.Emitter
+	err  error
+}
+
+func (s *emitting) Bind(e stage.Emitter) { s.emit = e }
+
+func (s *emitting) Process(ctx context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	s.err = s.emit.Emit(ctx, r)
+	return r, stage.Continue, nil
+}
+
+func TestChainEmitErrors(t *testing.T) {
+	s := &emitting{}
+	next := errors.New("sink failed")
+	c := NewChain([]stage.Stage{s}, func(context.Context, record.Record) error { return next })
+	if err := c.Emit(context.Background(), record.Record{}); !errors.Is(err, next) {
+		t.Fatalf("Emit error = %v, want %v", err, next)
+	}
+	if !errors.Is(s.err, next) {
+		t.Errorf("Emitter error = %v, want %v", s.err, next)
+	}
+	if st := c.Stats()[0]; st.EmitErrors != 1 || st.Errors != 0 {
+		t.Errorf("Stats() = %+v, want one emit error and no stage error", st)
+	}
+}
+
+// lookup returns the value of the last field of r named key.
+func lookup(r record.Record, key string) (any, bool) {
+	for i := len(r.Fields) - 1; i >= 0; i-- {
+		if r.Fields[i].Key == key {
+			return r.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
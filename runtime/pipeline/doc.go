@@ -0,0 +1,39 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package pipeline contains the runtime executor for dlog pipelines.
+//
+// Chain runs a record through an ordered list of stages and hands the
+// survivors to a terminal Handler (typically encoding and sink fan-out).
+// It implements the full stage contract: disabled stages are skipped,
+// Drop and Defer stop processing, and stage.Deferrer stages are bound to
+// an Emitter that resumes released records at the following stage.
+// BuildSteps builds the stages of a list of plugin specifications together
+// with their error policies (OnError), which the Chain applies to stage
+// errors and recovered panics, and their When conditions, which restrict
+// a stage to the records they match. Chain.Explain shows which stages
+// would process a given record.
+//
+// Router is the usual terminal Handler: it resolves the sinks and routes
+// of a pipeline.Specification against a Registry, compiles the route
+// conditions with package runtime/expr and delivers each record to the
+// sinks of the first (or every) matching route, or to the default sinks.
+// Each sink may have a binding with its own plugins (run by a Chain per
+// sink, with stage.SinkScoped stages specialized for it), encoder and
+// minimum level; sinks sharing an encoder and having no binding plugins
+// share one encoding of the record. BuildSteps, whose chains run before
+// any sink is selected, rejects stages with per-sink settings.
+package pipeline
@@ -0,0 +1,85 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"dirpx.dev/dlog/apis/sink"
+)
+
+var (
+	// ErrSinkUnknown is returned when a specification refers to a sink
+	// that is not registered.
+	ErrSinkUnknown = errors.New("dlog: unknown sink")
+
+	// ErrSinkDuplicate is returned when a sink name is registered twice.
+	ErrSinkDuplicate = errors.New("dlog: duplicate sink")
+)
+
+// Registry holds the sinks that pipeline specifications refer to by name.
+// It is safe for concurrent use.
+type Registry struct {
+	mu    sync.RWMutex
+	sinks map[string]sink.Sink
+}
+
+// NewRegistry returns a Registry holding sinks.
+func NewRegistry(sinks ...sink.Sink) (*Registry, error) {
+	r := &Registry{sinks: make(map[string]sink.Sink, len(sinks))}
+	for _, s := range sinks {
+		if err := r.Register(s); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds s under s.Name().
+func (r *Registry) Register(s sink.Sink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name := s.Name()
+	if _, ok := r.sinks[name]; ok {
+		return fmt.Errorf("%w: %q", ErrSinkDuplicate, name)
+	}
+	r.sinks[name] = s
+	return nil
+}
+
+// Lookup returns the sink registered under name.
+func (r *Registry) Lookup(name string) (sink.Sink, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.sinks[name]
+	return s, ok
+}
+
+// Names returns the registered sink names in sorted order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.sinks))
+	for name := range r.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
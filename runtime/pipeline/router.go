@@ -0,0 +1,251 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"dirpx.dev/dlog/apis/pipeline"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/apis/sink"
+	"dirpx.dev/dlog/runtime/encoder"
+	"dirpx.dev/dlog/runtime/expr"
+)
+
+var (
+	// ErrEncoderUnknown is returned when a binding names an encoder that
+	// is not configured.
+	ErrEncoderUnknown = errors.New("dlog: unknown encoder")
+)
+
+// RouterOptions configures NewRouter. A nil *RouterOptions uses defaults.
+type RouterOptions struct {
+	// Encoder encodes the entries of sinks whose binding names no
+	// encoder. If nil, encoder.NewJSON(nil) is used.
+	Encoder encoder.Encoder
+
+	// Encoders maps the encoder names used by bindings to encoders.
+	// "json" and "logfmt" default to encoder.NewJSON(nil) and
+	// encoder.NewLogfmt(nil).
+	Encoders map[string]encoder.Encoder
+
+	// Plugins are the builders of the plugins used by bindings, looked up
+	// by Kind.
+	Plugins []plugin.Builder
+}
+
+// Router is the terminal Handler of a Chain: it selects the sinks of a
+// record according to the routes of a pipeline.Specification and
+// delivers the record to each of them through its binding. It is safe for
+// concurrent use as long as its sinks and binding stages are.
+//
+// Sinks without binding plugins receive the record unchanged, so the
+// record is encoded once per distinct encoder among them; sinks with
+// binding plugins encode their own version of the record.
+type Router struct {
+	routes []route
+	all    bool
+	// fallback receives records matching no route (Specification.Sinks).
+	fallback []*target
+	// targets holds every bound sink, for Flush.
+	targets []*target
+	// encoders are the distinct encoders in use, indexed by target.enc.
+	encoders []encoder.Encoder
+}
+
+// route is a compiled pipeline.Route.
+type route struct {
+	name    string
+	when    *expr.Program
+	targets []*target
+}
+
+// NewRouter validates the routes, sinks and bindings of ps against reg
+// and returns a Router delivering to them. Without routes, every record
+// goes to ps.Sinks.
+func NewRouter(ctx context.Context, ps pipeline.Specification, reg *Registry, opts *RouterOptions) (*Router, error) {
+	if err := ps.Validate(); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &RouterOptions{}
+	}
+	b := &routerBuilder{
+		ctx:      ctx,
+		ps:       ps,
+		reg:      reg,
+		opts:     opts,
+		rt:       &Router{},
+		targets:  map[string]*target{},
+		encoders: map[string]int{},
+		plugins:  builderIndex(opts.Plugins),
+	}
+	def := opts.Encoder
+	if def == nil {
+		def = encoder.NewJSON(nil)
+	}
+	b.rt.encoders = append(b.rt.encoders, def)
+
+	names := make([]string, 0, len(ps.Bindings))
+	for name := range ps.Bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := b.target(name); err != nil {
+			return nil, err
+		}
+	}
+
+	rt := b.rt
+	rt.all = ps.RouteMode == pipeline.RouteAll
+	var err error
+	if rt.fallback, err = b.resolve(ps.Sinks); err != nil {
+		return nil, fmt.Errorf("dlog: sinks: %w", err)
+	}
+	for i, r := range ps.Routes {
+		prog, err := expr.Compile(r.When)
+		if err != nil {
+			return nil, fmt.Errorf("%w: routes[%d] %q: %w", pipeline.ErrRouteInvalid, i, r.Name, err)
+		}
+		ts, err := b.resolve(r.Sinks)
+		if err != nil {
+			return nil, fmt.Errorf("%w: routes[%d] %q: %w", pipeline.ErrRouteInvalid, i, r.Name, err)
+		}
+		rt.routes = append(rt.routes, route{name: r.Name, when: prog, targets: ts})
+	}
+	return rt, nil
+}
+
+// Select returns the sinks r is delivered to, in delivery order, before
+// the minimum levels of their bindings are applied.
+func (rt *Router) Select(r record.Record) []sink.Sink {
+	ts := rt.selectTargets(r)
+	out := make([]sink.Sink, len(ts))
+	for i, t := range ts {
+		out[i] = t.sink
+	}
+	return out
+}
+
+// Handle delivers r to the selected sinks. Errors are collected; a failing
+// sink does not prevent delivery to the others.
+func (rt *Router) Handle(ctx context.Context, r record.Record) error {
+	ts := rt.selectTargets(r)
+	if len(ts) == 0 {
+		return nil
+	}
+	var (
+		// entries caches the encoding of r per encoder.
+		buf     [4][]byte
+		entries = buf[:0]
+		errs    []error
+	)
+	if len(rt.encoders) > len(buf) {
+		entries = make([][]byte, 0, len(rt.encoders))
+	}
+	entries = entries[:len(rt.encoders)]
+	for _, t := range ts {
+		if r.Level < t.min {
+			continue
+		}
+		if t.chain != nil {
+			if err := t.chain.Emit(ctx, r); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if entries[t.enc] == nil {
+			// Entries are not reused: sinks may retain them.
+			entry, err := rt.encoders[t.enc].Append(nil, r)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("dlog: encode %s: %w", rt.encoders[t.enc].Name(), err))
+				continue
+			}
+			entries[t.enc] = entry
+		}
+		if err := t.write(ctx, entries[t.enc]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush flushes the binding stages and then every routed sink.
+func (rt *Router) Flush(ctx context.Context) error {
+	var errs []error
+	for _, t := range rt.targets {
+		if t.chain != nil {
+			if err := t.chain.Flush(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if err := t.sink.Flush(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("dlog: flush sink %q: %w", t.sink.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// selectTargets returns the targets of r according to the routes.
+func (rt *Router) selectTargets(r record.Record) []*target {
+	var (
+		out   []*target
+		owned bool
+	)
+	for i := range rt.routes {
+		route := &rt.routes[i]
+		if !route.when.Match(r) {
+			continue
+		}
+		if !rt.all {
+			return route.targets
+		}
+		if out == nil {
+			out = route.targets
+			continue
+		}
+		for _, t := range route.targets {
+			if containsTarget(out, t) {
+				continue
+			}
+			if !owned {
+				out = append([]*target(nil), out...)
+				owned = true
+			}
+			out = append(out, t)
+		}
+	}
+	if out == nil {
+		return rt.fallback
+	}
+	return out
+}
+
+// containsTarget reports whether ts holds t.
+func containsTarget(ts []*target, t *target) bool {
+	for _, x := range ts {
+		if x == t {
+			return true
+		}
+	}
+	return false
+}
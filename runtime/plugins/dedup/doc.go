@@ -0,0 +1,38 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package dedup implements the "dedup" plugin: suppression of repeated
+// records within a sliding window.
+//
+// Records are fingerprinted by level, message and the values of the field
+// keys listed in Config.Fields. The fingerprint is an unseeded FNV-1a
+// hash, so it is the same in every process and across restarts; it is
+// attached to passing records as fields.Fingerprint.
+//
+// The first occurrence of a fingerprint passes. Repeats that arrive less
+// than Window after the previous occurrence are suppressed, each one
+// extending the window. When the window closes, or MaxAge after the first
+// occurrence for records that never stop repeating, the stage emits a
+// follow-up record: the last suppressed repeat with fields.RepeatCount,
+// fields.FirstSeen and fields.LastSeen added. A crash loop that logs the
+// same line over and over therefore produces one line plus a summary.
+//
+// Follow-ups are emitted through the stage.Emitter bound by the pipeline
+// (see stage.Deferrer) by a sweep that runs on the record path at most
+// once per Window and on Flush. At most MaxEntries fingerprints are
+// tracked; when the limit is reached the least recently seen one is
+// evicted, emitting its follow-up early.
+package dedup
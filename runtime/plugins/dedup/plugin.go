@@ -0,0 +1,290 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "dedup"
+
+// Defaults applied to zero Config values.
+const (
+	DefaultWindow     = 10 * time.Second
+	DefaultMaxAge     = time.Minute
+	DefaultMaxEntries = 10000
+)
+
+var (
+	// ErrConfigInvalid is returned when a dedup configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid dedup config")
+)
+
+// Config is the configuration payload of the "dedup" plugin.
+type Config struct {
+	// Fields are the field keys whose values are part of the fingerprint,
+	// in addition to level and message.
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"`
+
+	// Window is the sliding suppression window as a time.ParseDuration
+	// string; default DefaultWindow.
+	Window string `json:"window,omitempty" yaml:"window,omitempty"`
+
+	// MaxAge bounds how long repeats are folded into one follow-up;
+	// default DefaultMaxAge.
+	MaxAge string `json:"max_age,omitempty" yaml:"max_age,omitempty"`
+
+	// MaxEntries bounds the number of tracked fingerprints; default
+	// DefaultMaxEntries.
+	MaxEntries int `json:"max_entries,omitempty" yaml:"max_entries,omitempty"`
+}
+
+// Builder builds "dedup" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "dedup".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// entry tracks one fingerprint.
+type entry struct {
+	fp        uint64
+	firstSeen time.Time
+	lastSeen  time.Time
+	repeats   uint64
+	// last is the most recent suppressed repeat, the follow-up template.
+	last record.Record
+	elem *list.Element
+}
+
+// Stage is the "dedup" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name       string
+	enabled    bool
+	keys       []string
+	window     time.Duration
+	maxAge     time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[uint64]*entry
+	order   list.List // of *entry, least recently seen first
+
+	nextSweep atomic.Int64
+	emit      atomic.Pointer[stage.Emitter]
+}
+
+var (
+	_ plugin.Deduplicator = (*Stage)(nil)
+	_ stage.Deferrer      = (*Stage)(nil)
+)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{
+		name:       name,
+		enabled:    enabled,
+		keys:       append([]string(nil), cfg.Fields...),
+		window:     DefaultWindow,
+		maxAge:     DefaultMaxAge,
+		maxEntries: DefaultMaxEntries,
+		now:        time.Now,
+		entries:    make(map[uint64]*entry),
+	}
+	for _, k := range s.keys {
+		if k == "" {
+			return nil, fmt.Errorf("%w: empty field key", ErrConfigInvalid)
+		}
+	}
+	var err error
+	if s.window, err = duration("window", cfg.Window, s.window); err != nil {
+		return nil, err
+	}
+	if s.maxAge, err = duration("max_age", cfg.MaxAge, s.maxAge); err != nil {
+		return nil, err
+	}
+	switch {
+	case cfg.MaxEntries < 0:
+		return nil, fmt.Errorf("%w: max_entries must not be negative", ErrConfigInvalid)
+	case cfg.MaxEntries > 0:
+		s.maxEntries = cfg.MaxEntries
+	}
+	return s, nil
+}
+
+// Bind sets the Emitter used for follow-up records.
+func (s *Stage) Bind(e stage.Emitter) { s.emit.Store(&e) }
+
+// Process passes the first occurrence of r's fingerprint and suppresses
+// repeats within the window.
+func (s *Stage) Process(ctx context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	now := s.now()
+	fp := s.fingerprint(r)
+	var out []record.Record
+
+	s.mu.Lock()
+	if next := s.nextSweep.Load(); now.UnixNano() >= next {
+		s.nextSweep.Store(now.Add(s.window).UnixNano())
+		out = s.sweepLocked(now, out)
+	}
+	e := s.entries[fp]
+	if e != nil && now.Sub(e.lastSeen) < s.window && now.Sub(e.firstSeen) < s.maxAge {
+		e.lastSeen = now
+		e.repeats++
+		e.last = r
+		s.order.MoveToBack(e.elem)
+		s.mu.Unlock()
+		_ = s.release(ctx, out)
+		return r, stage.Drop, nil
+	}
+	if e != nil {
+		out = s.removeLocked(e, out)
+	}
+	for s.order.Len() >= s.maxEntries {
+		out = s.removeLocked(s.order.Front().Value.(*entry), out)
+	}
+	e = &entry{fp: fp, firstSeen: now, lastSeen: now}
+	e.elem = s.order.PushBack(e)
+	s.entries[fp] = e
+	s.mu.Unlock()
+
+	// Follow-ups concern other records: a failure to deliver them is the
+	// pipeline's to account for (see stage.Emitter), not r's.
+	_ = s.release(ctx, out)
+	return r.WithFields(field.New(fields.Fingerprint, formatFingerprint(fp))), stage.Continue, nil
+}
+
+// Flush emits the follow-ups of all fingerprints whose window has closed.
+func (s *Stage) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	out := s.sweepLocked(s.now(), nil)
+	s.mu.Unlock()
+	return s.release(ctx, out)
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// fingerprint hashes the level, message and configured fields of r.
+// Field values are formatted with fmt, which prints maps in key order, so
+// the result is deterministic.
+func (s *Stage) fingerprint(r record.Record) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(r.Level.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(r.Message))
+	for _, k := range s.keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		for i := len(r.Fields) - 1; i >= 0; i-- {
+			if r.Fields[i].Key == k {
+				h.Write([]byte{'='})
+				fmt.Fprint(h, r.Fields[i].Value)
+				break
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+// sweepLocked removes the fingerprints whose window has closed, appending
+// their follow-ups to out. Entries are ordered by lastSeen, so the sweep
+// stops at the first live entry; entries past MaxAge are found when they
+// repeat.
+func (s *Stage) sweepLocked(now time.Time, out []record.Record) []record.Record {
+	for f := s.order.Front(); f != nil; f = s.order.Front() {
+		e := f.Value.(*entry)
+		if now.Sub(e.lastSeen) < s.window {
+			break
+		}
+		out = s.removeLocked(e, out)
+	}
+	return out
+}
+
+// removeLocked forgets e, appending its follow-up to out if it had
+// suppressed repeats.
+func (s *Stage) removeLocked(e *entry, out []record.Record) []record.Record {
+	s.order.Remove(e.elem)
+	delete(s.entries, e.fp)
+	if e.repeats == 0 {
+		return out
+	}
+	return append(out, e.last.WithFields(
+		field.New(fields.Fingerprint, formatFingerprint(e.fp)),
+		field.New(fields.RepeatCount, e.repeats),
+		field.New(fields.FirstSeen, e.firstSeen),
+		field.New(fields.LastSeen, e.lastSeen),
+	))
+}
+
+// release emits follow-ups if the stage is bound; otherwise they are lost.
+func (s *Stage) release(ctx context.Context, rs []record.Record) error {
+	if len(rs) == 0 {
+		return nil
+	}
+	if e := s.emit.Load(); e != nil {
+		return (*e).Emit(ctx, rs...)
+	}
+	return nil
+}
+
+// duration parses an optional positive duration.
+func duration(name, s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("%w: %s %q must be a positive duration", ErrConfigInvalid, name, s)
+	}
+	return d, nil
+}
+
+// formatFingerprint renders a fingerprint as 16 hex digits.
+func formatFingerprint(fp uint64) string {
+	return fmt.Sprintf("%016x", fp)
+}
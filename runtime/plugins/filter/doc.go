@@ -0,0 +1,27 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package filter implements the "filter" plugin, which keeps or drops
+// records matching an expression of package runtime/expr:
+//
+//	{"expr": "level >= warn || ctx.component == \"auth\""}
+//
+// With the default action "keep" only matching records continue through
+// the pipeline; with action "drop" matching records are discarded and all
+// others continue. The expression is compiled by Build, so syntax and type
+// errors are reported together with the plugin name before any record is
+// processed.
+package filter
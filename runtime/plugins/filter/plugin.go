@@ -0,0 +1,115 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/expr"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "filter"
+
+// Actions applied to records matching the expression.
+const (
+	ActionKeep = "keep"
+	ActionDrop = "drop"
+)
+
+var (
+	// ErrConfigInvalid is returned when a filter configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid filter config")
+)
+
+// Config is the configuration payload of the "filter" plugin.
+type Config struct {
+	// Expr is the boolean expression evaluated against every record.
+	Expr string `json:"expr" yaml:"expr"`
+
+	// Action is ActionKeep (default) or ActionDrop.
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+}
+
+// Builder builds "filter" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "filter".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and compiles the expression.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "filter" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name    string
+	enabled bool
+	prog    *expr.Program
+	// drop is the decision for matching records: true for ActionDrop.
+	drop bool
+}
+
+var _ plugin.Filter = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{name: name, enabled: enabled}
+	switch cfg.Action {
+	case "", ActionKeep:
+	case ActionDrop:
+		s.drop = true
+	default:
+		return nil, fmt.Errorf("%w: %s: unknown action %q", ErrConfigInvalid, name, cfg.Action)
+	}
+	if cfg.Expr == "" {
+		return nil, fmt.Errorf("%w: %s: expr is required", ErrConfigInvalid, name)
+	}
+	prog, err := expr.Compile(cfg.Expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrConfigInvalid, name, err)
+	}
+	s.prog = prog
+	return s, nil
+}
+
+// Process keeps or drops r according to the expression and action.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	if s.prog.Match(r) == s.drop {
+		return r, stage.Drop, nil
+	}
+	return r, stage.Continue, nil
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
@@ -0,0 +1,43 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package levelfilter implements the "level_filter" plugin: a
+// hierarchical, vmodule-style minimum level keyed by the Component and
+// Subsystem attributes of the record's context Pack.
+//
+// Rules are written as a comma-separated list of selector:level pairs:
+//
+//	component=ingress:debug, subsystem=jwt:trace, *:info
+//
+// A selector is "*" or one or more conditions joined by "&", each of the
+// form component=PATTERN or subsystem=PATTERN. Patterns are exact names
+// or globs using path.Match syntax (for example "ingress-*").
+//
+// When several rules match, the most specific one wins:
+//
+//  1. rules constraining both component and subsystem;
+//  2. rules constraining only the subsystem (the finer-grained part);
+//  3. rules constraining only the component;
+//  4. the "*" rule.
+//
+// Within each group exact names beat globs, and longer globs beat shorter
+// ones. Records that match no rule pass through unchanged.
+//
+// Rules are compiled into hash lookups, so exact-name rules cost a few map
+// reads per record. The stage can follow a provider.Stream (see
+// Stage.Watch) and swap its rules atomically without rebuilding the
+// pipeline.
+package levelfilter
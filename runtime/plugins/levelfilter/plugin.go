@@ -0,0 +1,123 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package levelfilter
+
+import (
+	"context"
+	"sync/atomic"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/provider"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "level_filter"
+
+// Config is the configuration payload of the "level_filter" plugin.
+type Config struct {
+	// Rules is the comma-separated rule list, e.g.
+	// "component=ingress:debug, subsystem=jwt:trace, *:info".
+	Rules string `json:"rules" yaml:"rules"`
+}
+
+// Builder builds "level_filter" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "level_filter".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and compiles its rules.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "level_filter" pipeline stage.
+// It is safe for concurrent use; rules can be replaced at any time.
+type Stage struct {
+	name    string
+	enabled atomic.Bool
+	rules   atomic.Pointer[table]
+}
+
+var _ plugin.Filter = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and initial state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	t, err := compile(cfg.Rules)
+	if err != nil {
+		return nil, err
+	}
+	s := &Stage{name: name}
+	s.rules.Store(t)
+	s.enabled.Store(enabled)
+	return s, nil
+}
+
+// Process drops records below the minimum level selected by the rules
+// for the record's component and subsystem.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	if lvl, ok := s.rules.Load().minLevel(r.Ctx.Component, r.Ctx.Subsystem); ok && r.Level < lvl {
+		return r, stage.Drop, nil
+	}
+	return r, stage.Continue, nil
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled.Load() }
+
+// Update compiles cfg and atomically replaces the current rules.
+// On error the current rules are kept.
+func (s *Stage) Update(cfg Config) error {
+	t, err := compile(cfg.Rules)
+	if err != nil {
+		return err
+	}
+	s.rules.Store(t)
+	return nil
+}
+
+// Watch follows stream and applies every published version of this
+// plugin's specification (matched by Kind and Name), including its
+// Enabled flag. Invalid configurations are reported to onError, which may
+// be nil, and leave the current rules in place.
+//
+// Watch blocks until ctx is done or the stream is closed.
+func (s *Stage) Watch(ctx context.Context, stream provider.Stream, onError func(error)) error {
+	return spec.Watch(ctx, stream, Kind, s.name, func(ps plugin.Specification) error {
+		var cfg Config
+		if err := spec.Decode(ps.Config, &cfg); err != nil {
+			return err
+		}
+		if err := s.Update(cfg); err != nil {
+			return err
+		}
+		s.enabled.Store(spec.Enabled(ps))
+		return nil
+	}, onError)
+}
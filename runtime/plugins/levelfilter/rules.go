@@ -0,0 +1,203 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package levelfilter
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"dirpx.dev/dlog/apis/level"
+)
+
+var (
+	// ErrRuleInvalid is returned when a rule cannot be parsed.
+	ErrRuleInvalid = errors.New("dlog: invalid level_filter rule")
+)
+
+// pair is the lookup key for rules constraining both dimensions.
+type pair struct {
+	component, subsystem string
+}
+
+// globRule is a rule with at least one glob pattern; an empty pattern
+// means the dimension is unconstrained.
+type globRule struct {
+	component, subsystem string
+	level                level.Level
+	// weight orders glob rules within a group: longer patterns first.
+	weight int
+}
+
+// table is a compiled, immutable rule set.
+type table struct {
+	both      map[pair]level.Level
+	bothGlobs []globRule
+	sub       map[string]level.Level
+	subGlobs  []globRule
+	comp      map[string]level.Level
+	compGlobs []globRule
+	any       level.Level
+	hasAny    bool
+}
+
+// minLevel returns the minimum level for the given component and
+// subsystem and whether any rule matched.
+func (t *table) minLevel(component, subsystem string) (level.Level, bool) {
+	if component != "" && subsystem != "" {
+		if l, ok := t.both[pair{component, subsystem}]; ok {
+			return l, true
+		}
+		for _, g := range t.bothGlobs {
+			if match(g.component, component) && match(g.subsystem, subsystem) {
+				return g.level, true
+			}
+		}
+	}
+	if subsystem != "" {
+		if l, ok := t.sub[subsystem]; ok {
+			return l, true
+		}
+		for _, g := range t.subGlobs {
+			if match(g.subsystem, subsystem) {
+				return g.level, true
+			}
+		}
+	}
+	if component != "" {
+		if l, ok := t.comp[component]; ok {
+			return l, true
+		}
+		for _, g := range t.compGlobs {
+			if match(g.component, component) {
+				return g.level, true
+			}
+		}
+	}
+	return t.any, t.hasAny
+}
+
+// compile parses rules and builds a lookup table.
+// When two rules have the same selector, the first one wins.
+func compile(rules string) (*table, error) {
+	t := &table{
+		both: map[pair]level.Level{},
+		sub:  map[string]level.Level{},
+		comp: map[string]level.Level{},
+	}
+	for _, raw := range strings.Split(rules, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		if err := t.add(raw); err != nil {
+			return nil, err
+		}
+	}
+	for _, gs := range [][]globRule{t.bothGlobs, t.subGlobs, t.compGlobs} {
+		sort.SliceStable(gs, func(i, j int) bool { return gs[i].weight > gs[j].weight })
+	}
+	return t, nil
+}
+
+// add parses a single selector:level rule into t.
+func (t *table) add(raw string) error {
+	i := strings.LastIndexByte(raw, ':')
+	if i < 0 {
+		return fmt.Errorf("%w: %q: missing \":level\"", ErrRuleInvalid, raw)
+	}
+	sel, lvlText := strings.TrimSpace(raw[:i]), raw[i+1:]
+	lvl, err := level.ParseLevel(lvlText)
+	if err != nil {
+		return fmt.Errorf("%w: %q: %w", ErrRuleInvalid, raw, err)
+	}
+
+	if sel == "*" {
+		if !t.hasAny {
+			t.any, t.hasAny = lvl, true
+		}
+		return nil
+	}
+
+	var component, subsystem string
+	for _, cond := range strings.Split(sel, "&") {
+		k, v, ok := strings.Cut(strings.TrimSpace(cond), "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || v == "" {
+			return fmt.Errorf("%w: %q: condition %q must be key=pattern", ErrRuleInvalid, raw, cond)
+		}
+		if _, err := path.Match(v, ""); err != nil {
+			return fmt.Errorf("%w: %q: pattern %q: %w", ErrRuleInvalid, raw, v, err)
+		}
+		switch k {
+		case "component":
+			component = v
+		case "subsystem":
+			subsystem = v
+		default:
+			return fmt.Errorf("%w: %q: unknown key %q (want component or subsystem)", ErrRuleInvalid, raw, k)
+		}
+	}
+
+	glob := isGlob(component) || isGlob(subsystem)
+	g := globRule{component: component, subsystem: subsystem, level: lvl, weight: len(component) + len(subsystem)}
+	switch {
+	case component != "" && subsystem != "":
+		if glob {
+			t.bothGlobs = append(t.bothGlobs, g)
+		} else {
+			setOnce(t.both, pair{component, subsystem}, lvl)
+		}
+	case subsystem != "":
+		if glob {
+			t.subGlobs = append(t.subGlobs, g)
+		} else {
+			setOnce(t.sub, subsystem, lvl)
+		}
+	default:
+		if glob {
+			t.compGlobs = append(t.compGlobs, g)
+		} else {
+			setOnce(t.comp, component, lvl)
+		}
+	}
+	return nil
+}
+
+// setOnce stores v under k unless k is already present.
+func setOnce[K comparable](m map[K]level.Level, k K, v level.Level) {
+	if _, ok := m[k]; !ok {
+		m[k] = v
+	}
+}
+
+// isGlob reports whether p contains path.Match metacharacters.
+func isGlob(p string) bool {
+	return strings.ContainsAny(p, `*?[\`)
+}
+
+// match reports whether name matches the pattern; an empty pattern
+// matches everything. Patterns are validated at compile time.
+func match(pattern, name string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package limits implements the "limits" plugin, which bounds the size of
+// records so that one oversized entry cannot break a sink.
+//
+// The following limits are supported; zero disables a limit:
+//
+//   - MaxMessage: bytes of the message;
+//   - MaxFields: number of record fields (extra fields are dropped from
+//     the end);
+//   - MaxValue: bytes of every string or []byte value, including values
+//     nested in maps and slices;
+//   - MaxDepth: nesting depth of map and slice values; deeper levels are
+//     replaced by the marker;
+//   - MaxSize: bytes of the whole record, as encoded by the JSON encoder
+//     with flat errors. The size is estimated without encoding the
+//     record, erring on the large side (time stamps count as the longest
+//     RFC 3339 time, callers with their full path). Fields are dropped
+//     from the end, then the error text and the message are shortened
+//     until the record fits.
+//
+// Shortened strings end with Marker (default DefaultMarker) and are cut at
+// a UTF-8 boundary. A record that was changed gets a fields.Truncated
+// field listing what was cut: "msg", "error", "fields", "size" or the key
+// of a field whose value was shortened.
+//
+// Sinks have different hard limits, so Config.Sinks holds per-sink
+// overrides: every non-zero limit of an override replaces the base one.
+// Stage implements stage.SinkScoped: Stage.ForSink returns the variant of
+// the stage for a sink. Overrides only take effect in the plugins of a
+// sink binding; runtime/pipeline.BuildSteps rejects a stage with
+// overrides, since its chain runs before any sink is selected.
+package limits
@@ -0,0 +1,227 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package limits
+
+import (
+	"unicode/utf8"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// limiter applies a set of limits to records. It is immutable.
+type limiter struct {
+	Limits
+	marker string
+}
+
+func newLimiter(l Limits, marker string) limiter {
+	return limiter{Limits: l, marker: marker}
+}
+
+// apply returns r shortened to fit the limits. r's field slice and the
+// maps and slices it references are never modified.
+func (l limiter) apply(r record.Record) record.Record {
+	var cut []string
+
+	if l.MaxMessage > 0 {
+		if s, ok := l.truncate(r.Message, l.MaxMessage); ok {
+			r.Message = s
+			cut = append(cut, fields.Message)
+		}
+	}
+	if l.MaxFields > 0 && len(r.Fields) > l.MaxFields {
+		r.Fields = r.Fields[:l.MaxFields:l.MaxFields]
+		cut = append(cut, "fields")
+	}
+	if l.MaxValue > 0 || l.MaxDepth > 0 {
+		var out []field.Field
+		for i, f := range r.Fields {
+			v, changed := l.value(f.Value, 1)
+			if changed && out == nil {
+				out = make([]field.Field, len(r.Fields))
+				copy(out, r.Fields)
+			}
+			if changed {
+				out[i] = field.New(f.Key, v)
+				cut = append(cut, f.Key)
+			}
+		}
+		if out != nil {
+			r.Fields = out
+		}
+	}
+	if l.MaxSize > 0 {
+		r, cut = l.fit(r, cut)
+	}
+
+	if len(cut) == 0 {
+		return r
+	}
+	return r.WithFields(field.New(fields.Truncated, cut))
+}
+
+// fit drops fields from the end, then shortens the error text and the
+// message until the estimated JSON size of r (see sizeOf), including the
+// fields.Truncated field, fits MaxSize. Sizes are computed once and kept
+// up to date, so fit is linear in the size of r.
+func (l limiter) fit(r record.Record, cut []string) (record.Record, []string) {
+	sz := sizeOf(r)
+	size := sz.total(len(r.Fields))
+	over := func() int {
+		if len(cut) == 0 {
+			return size - l.MaxSize
+		}
+		return size + cutLen(cut) - l.MaxSize
+	}
+	if over() <= 0 {
+		return r, cut
+	}
+	cut = appendCut(cut, "size")
+
+	n := len(r.Fields)
+	for n > 0 && over() > 0 {
+		n--
+		size -= sz.fields[n]
+	}
+	r.Fields = r.Fields[:n:n]
+	if r.Err != nil && over() > 0 {
+		cut = appendCut(cut, fields.Error)
+		text := sz.err - len(`,"error":`)
+		t := l.fitString(r.Err.Error(), text-2-over())
+		r.Err = truncatedError(t)
+		size += strLen(t, false) - text
+	}
+	if over() > 0 {
+		cut = appendCut(cut, fields.Message)
+		t := l.fitString(r.Message, sz.msg-2-over())
+		r.Message = t
+		size += strLen(t, false) - sz.msg
+	}
+	return r, cut
+}
+
+// fitString shortens s so that its escaped JSON text, without quotes, takes
+// at most budget bytes including the marker, cutting at a UTF-8 boundary.
+// If not even the marker fits, the result is the marker alone.
+func (l limiter) fitString(s string, budget int) string {
+	budget -= strLen(l.marker, false) - 2
+	end := 0
+	for end < len(s) {
+		w, size := runeLen(s[end:], false)
+		if w > budget {
+			break
+		}
+		budget -= w
+		end += size
+	}
+	return s[:end] + l.marker
+}
+
+// appendCut adds what to cut unless it is already listed.
+func appendCut(cut []string, what string) []string {
+	for _, c := range cut {
+		if c == what {
+			return cut
+		}
+	}
+	return append(cut, what)
+}
+
+// value applies MaxValue and MaxDepth to v, found at the given depth.
+func (l limiter) value(v any, depth int) (any, bool) {
+	switch x := v.(type) {
+	case string:
+		if l.MaxValue > 0 {
+			return l.truncate(x, l.MaxValue)
+		}
+		return v, false
+	case []byte:
+		if l.MaxValue > 0 && len(x) > l.MaxValue {
+			s, _ := l.truncate(string(x), l.MaxValue)
+			return s, true
+		}
+		return v, false
+	case map[string]any:
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return l.marker, true
+		}
+		var out map[string]any
+		for k, mv := range x {
+			nv, changed := l.value(mv, depth+1)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = make(map[string]any, len(x))
+				for k2, v2 := range x {
+					out[k2] = v2
+				}
+			}
+			out[k] = nv
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []any:
+		if l.MaxDepth > 0 && depth > l.MaxDepth {
+			return l.marker, true
+		}
+		var out []any
+		for i, sv := range x {
+			nv, changed := l.value(sv, depth+1)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = append([]any(nil), x...)
+			}
+			out[i] = nv
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	default:
+		return v, false
+	}
+}
+
+// truncate shortens s to at most limit bytes including the marker,
+// cutting at a UTF-8 boundary. If limit is smaller than the marker, the
+// result is the marker alone.
+func (l limiter) truncate(s string, limit int) (string, bool) {
+	if len(s) <= limit {
+		return s, false
+	}
+	keep := limit - len(l.marker)
+	if keep <= 0 {
+		return l.marker, true
+	}
+	for keep > 0 && !utf8.RuneStart(s[keep]) {
+		keep--
+	}
+	return s[:keep] + l.marker, true
+}
+
+// truncatedError replaces an error whose text was shortened.
+type truncatedError string
+
+// Error returns the shortened text.
+func (e truncatedError) Error() string { return string(e) }
@@ -0,0 +1,102 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package limits
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+)
+
+func TestFitString(t *testing.T) {
+	l := newLimiter(Limits{}, "~")
+	tests := []struct {
+		s      string
+		budget int
+		want   string
+	}{
+		{"abcdef", 4, "abc~"},
+		{"ab\u2028cd", 6, "ab~"},
+		{"ab\u2028cd", 9, "ab\u2028~"},
+		{"héllo", 3, "h~"},
+		{"a\"b", 3, "a~"},
+		{"\xff\xffa", 4, "\xff~"},
+		{"abc", 0, "~"},
+	}
+	for _, tt := range tests {
+		if got := l.fitString(tt.s, tt.budget); got != tt.want {
+			t.Errorf("fitString(%q, %d) = %q, want %q", tt.s, tt.budget, got, tt.want)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	base := record.Record{
+		Level:   level.Error,
+		Message: strings.Repeat("m\"", 100),
+		Err:     errors.New(strings.Repeat("e", 300)),
+		Fields: []field.Field{
+			field.New("a", strings.Repeat("x", 50)),
+			field.New("b", map[string]any{"k": "<v>"}),
+			field.New("c", 42),
+		},
+	}
+	invalid := record.Record{Message: strings.Repeat("\xff", 200)}
+	separators := record.Record{Message: strings.Repeat("ab\u2028", 100)}
+
+	for _, r := range []record.Record{base, invalid, separators} {
+		for _, max := range []int{60, 100, 150, 200, 300, 500, 1000} {
+			out := newLimiter(Limits{MaxSize: max}, DefaultMarker).apply(r)
+			n := encoded(t, out)
+			if n > max && out.Message != DefaultMarker {
+				t.Errorf("max_size %d: encoded %d bytes: %q", max, n, out.Message)
+			}
+			if utf8.ValidString(r.Message) && !utf8.ValidString(out.Message) {
+				t.Errorf("max_size %d: message cut inside a rune: %q", max, out.Message)
+			}
+			if n < encoded(t, r) {
+				if _, ok := lookup(out, fields.Truncated); !ok {
+					t.Errorf("max_size %d: shortened record without %s", max, fields.Truncated)
+				}
+			}
+		}
+	}
+}
+
+func TestFitKeepsRecordsThatFit(t *testing.T) {
+	r := record.Record{Message: "short", Fields: []field.Field{field.New("a", 1)}}
+	out := newLimiter(Limits{MaxSize: encoded(t, r)}, DefaultMarker).apply(r)
+	if out.Message != r.Message || len(out.Fields) != len(r.Fields) {
+		t.Errorf("apply changed a fitting record: %+v", out)
+	}
+}
+
+// lookup returns the value of the last field of r named key.
+func lookup(r record.Record, key string) (any, bool) {
+	for i := len(r.Fields) - 1; i >= 0; i-- {
+		if r.Fields[i].Key == key {
+			return r.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,193 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "limits"
+
+// DefaultMarker is appended to shortened strings.
+const DefaultMarker = "…[truncated]"
+
+var (
+	// ErrConfigInvalid is returned when a limits configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid limits config")
+)
+
+// Limits is a set of size limits; zero disables a limit.
+type Limits struct {
+	MaxMessage int `json:"max_message,omitempty" yaml:"max_message,omitempty"`
+	MaxFields  int `json:"max_fields,omitempty" yaml:"max_fields,omitempty"`
+	MaxValue   int `json:"max_value,omitempty" yaml:"max_value,omitempty"`
+	MaxDepth   int `json:"max_depth,omitempty" yaml:"max_depth,omitempty"`
+	MaxSize    int `json:"max_size,omitempty" yaml:"max_size,omitempty"`
+}
+
+// Config is the configuration payload of the "limits" plugin.
+type Config struct {
+	// Limits apply to every record.
+	Limits `yaml:",inline"`
+
+	// Marker is appended to shortened strings; default DefaultMarker.
+	Marker string `json:"marker,omitempty" yaml:"marker,omitempty"`
+
+	// Sinks maps sink names to overrides of Limits.
+	Sinks map[string]Limits `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// Builder builds "limits" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "limits".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "limits" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name    string
+	enabled bool
+	lim     limiter
+	sinks   map[string]Limits
+
+	mu      sync.Mutex
+	derived map[string]*Stage
+}
+
+var (
+	_ plugin.Plugin    = (*Stage)(nil)
+	_ stage.SinkScoped = (*Stage)(nil)
+)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	if err := check("", cfg.Limits); err != nil {
+		return nil, err
+	}
+	for sink, l := range cfg.Sinks {
+		if err := check("sinks."+sink+".", l); err != nil {
+			return nil, err
+		}
+	}
+	marker := cfg.Marker
+	if marker == "" {
+		marker = DefaultMarker
+	}
+	return &Stage{
+		name:    name,
+		enabled: enabled,
+		lim:     newLimiter(cfg.Limits, marker),
+		sinks:   cfg.Sinks,
+		derived: make(map[string]*Stage),
+	}, nil
+}
+
+// Process shortens r to fit the limits.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	return s.lim.apply(r), stage.Continue, nil
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// ForSink returns the variant of the stage with the overrides for sink
+// applied, or s itself if the sink has none.
+func (s *Stage) ForSink(sink string) stage.Stage {
+	o, ok := s.sinks[sink]
+	if !ok {
+		return s
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d, ok := s.derived[sink]; ok {
+		return d
+	}
+	d := &Stage{
+		name:    s.name,
+		enabled: s.enabled,
+		lim:     newLimiter(override(s.lim.Limits, o), s.lim.marker),
+	}
+	s.derived[sink] = d
+	return d
+}
+
+// Sinks returns the names of the sinks with overrides, sorted.
+func (s *Stage) Sinks() []string {
+	names := make([]string, 0, len(s.sinks))
+	for name := range s.sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// override returns base with the non-zero limits of o applied.
+func override(base, o Limits) Limits {
+	pick := func(b, v int) int {
+		if v != 0 {
+			return v
+		}
+		return b
+	}
+	return Limits{
+		MaxMessage: pick(base.MaxMessage, o.MaxMessage),
+		MaxFields:  pick(base.MaxFields, o.MaxFields),
+		MaxValue:   pick(base.MaxValue, o.MaxValue),
+		MaxDepth:   pick(base.MaxDepth, o.MaxDepth),
+		MaxSize:    pick(base.MaxSize, o.MaxSize),
+	}
+}
+
+// check rejects negative limits.
+func check(prefix string, l Limits) error {
+	for name, v := range map[string]int{
+		"max_message": l.MaxMessage,
+		"max_fields":  l.MaxFields,
+		"max_value":   l.MaxValue,
+		"max_depth":   l.MaxDepth,
+		"max_size":    l.MaxSize,
+	} {
+		if v < 0 {
+			return fmt.Errorf("%w: %s%s must not be negative", ErrConfigInvalid, prefix, name)
+		}
+	}
+	return nil
+}
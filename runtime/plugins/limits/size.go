@@ -0,0 +1,270 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package limits
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// The functions below estimate the length of the encoder.JSON encoding
+// of records, with flat errors, without encoding them. Estimates never
+// fall below the encoded length: time stamps count as the longest
+// RFC 3339 time and callers with their full path.
+
+// maxTimeLen is the length of the longest time.RFC3339Nano time, quoted.
+var maxTimeLen = len(time.RFC3339Nano) + 2
+
+// recordSize is the estimated size of a record, split into the parts fit
+// can shorten.
+type recordSize struct {
+	// base covers everything but the record fields, message and error.
+	base   int
+	fields []int // per record field, including its separator
+	msg    int
+	err    int // including key and separator; 0 without an error
+}
+
+// total returns the estimated size with the first n record fields.
+func (s *recordSize) total(n int) int {
+	t := s.base + s.msg + s.err
+	for _, f := range s.fields[:n] {
+		t += f
+	}
+	return t
+}
+
+// sizeOf estimates the encoded size of r.
+func sizeOf(r record.Record) recordSize {
+	// {"level":"...","msg":} followed by a newline.
+	s := recordSize{base: len(`{"level":`) + strLen(r.Level.String(), false) + len(`,"msg":}`) + 1}
+	if !r.Time.IsZero() {
+		s.base += len(`"ts":,`) + maxTimeLen
+	}
+	if r.Caller.File != "" {
+		s.base += len(`,"caller":`) + strLen(r.Caller.File, false) + 1 + intLen(int64(r.Caller.Line))
+	}
+	if r.Caller.Function != "" {
+		s.base += len(`,"func":`) + strLen(r.Caller.Function, false)
+	}
+	for _, f := range r.Ctx.Fields() {
+		s.base += fieldLen(f)
+	}
+	if len(r.Stack) > 0 {
+		s.base += len(`,"stack":[]`) + len(r.Stack) - 1
+		for _, f := range r.Stack {
+			s.base += frameLen(f)
+		}
+	}
+	s.msg = strLen(r.Message, false)
+	if r.Err != nil {
+		s.err = len(`,"error":`) + strLen(r.Err.Error(), false)
+	}
+	s.fields = make([]int, len(r.Fields))
+	for i, f := range r.Fields {
+		s.fields[i] = fieldLen(f)
+	}
+	return s
+}
+
+// cutLen estimates the size of the fields.Truncated field listing cut.
+func cutLen(cut []string) int {
+	n := len(`,"`+fields.Truncated+`":[]`) + len(cut) - 1
+	for _, c := range cut {
+		n += strLen(c, true)
+	}
+	return n
+}
+
+// fieldLen estimates the size of f as an object member, including the
+// separator before it.
+func fieldLen(f field.Field) int {
+	return 1 + strLen(f.Key, false) + 1 + valueLen(f.Value, false)
+}
+
+// frameLen estimates the size of f as encoded by encoding/json.
+func frameLen(f record.Frame) int {
+	n := len(`{}`)
+	sep := 0
+	if f.Function != "" {
+		n += len(`"function":`) + strLen(f.Function, true)
+		sep++
+	}
+	if f.File != "" {
+		n += len(`"file":`) + strLen(f.File, true)
+		sep++
+	}
+	if f.Line != 0 {
+		n += len(`"line":`) + intLen(int64(f.Line))
+		sep++
+	}
+	return n + max(sep-1, 0)
+}
+
+// valueLen estimates the size of v. Values nested in maps and slices are
+// encoded by encoding/json, which escapes HTML characters: html selects
+// its rules for strings.
+func valueLen(v any, html bool) int {
+	switch x := v.(type) {
+	case nil:
+		return len("null")
+	case string:
+		return strLen(x, html)
+	case bool:
+		if x {
+			return len("true")
+		}
+		return len("false")
+	case int:
+		return intLen(int64(x))
+	case int8:
+		return intLen(int64(x))
+	case int16:
+		return intLen(int64(x))
+	case int32:
+		return intLen(int64(x))
+	case int64:
+		return intLen(x)
+	case uint:
+		return uintLen(uint64(x))
+	case uint8:
+		return uintLen(uint64(x))
+	case uint16:
+		return uintLen(uint64(x))
+	case uint32:
+		return uintLen(uint64(x))
+	case uint64:
+		return uintLen(x)
+	case time.Time:
+		return maxTimeLen
+	case float32:
+		if !html {
+			return floatLen(float64(x), 32)
+		}
+	case float64:
+		if !html {
+			return floatLen(x, 64)
+		}
+	case time.Duration:
+		if html {
+			return intLen(int64(x))
+		}
+		return len(x.String()) + 2
+	case map[string]any:
+		n := len(`{}`) + max(len(x)-1, 0)
+		for k, mv := range x {
+			n += strLen(k, true) + 1 + valueLen(mv, true)
+		}
+		return n
+	case []any:
+		n := len(`[]`) + max(len(x)-1, 0)
+		for _, sv := range x {
+			n += valueLen(sv, true)
+		}
+		return n
+	case []string:
+		n := len(`[]`) + max(len(x)-1, 0)
+		for _, sv := range x {
+			n += strLen(sv, true)
+		}
+		return n
+	case error:
+		if !html {
+			return strLen(x.Error(), false)
+		}
+	case json.Marshaler, encoding.TextMarshaler:
+		// encoded by encoding/json below
+	case fmt.Stringer:
+		if !html {
+			return strLen(x.String(), false)
+		}
+	}
+	// Everything else is rare enough to be encoded.
+	b, err := json.Marshal(v)
+	if err != nil {
+		return strLen(fmt.Sprintf("%+v", v), false)
+	}
+	return len(b)
+}
+
+// floatLen returns the size of f as written by encoder.JSON.
+func floatLen(f float64, bits int) int {
+	var buf [32]byte
+	b := strconv.AppendFloat(buf[:0], f, 'g', -1, bits)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return len(b) + 2
+	}
+	return len(b)
+}
+
+// intLen returns the number of bytes of the decimal form of n.
+func intLen(n int64) int {
+	var buf [20]byte
+	return len(strconv.AppendInt(buf[:0], n, 10))
+}
+
+// uintLen returns the number of bytes of the decimal form of n.
+func uintLen(n uint64) int {
+	var buf [20]byte
+	return len(strconv.AppendUint(buf[:0], n, 10))
+}
+
+// strLen returns the size of s as a quoted JSON string. With html, the
+// characters encoding/json escapes in addition are counted as escapes.
+func strLen(s string, html bool) int {
+	n := 2
+	for i := 0; i < len(s); {
+		w, size := runeLen(s[i:], html)
+		n += w
+		i += size
+	}
+	return n
+}
+
+// runeLen returns the escaped size of the first rune of s and its length
+// in s.
+func runeLen(s string, html bool) (int, int) {
+	c := s[0]
+	if c < utf8.RuneSelf {
+		switch {
+		case c == '"' || c == '\\' || c == '\n' || c == '\r' || c == '\t':
+			return 2, 1
+		case c < 0x20, html && (c == '<' || c == '>' || c == '&'):
+			return 6, 1
+		}
+		return 1, 1
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	switch {
+	case r == utf8.RuneError && size == 1:
+		// Replaced by U+FFFD.
+		return 3, 1
+	case r == '\u2028' || r == '\u2029':
+		// Escaped by encoding/json only; counted for both.
+		return 6, size
+	}
+	return size, size
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package limits
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/encoder"
+)
+
+// stringer is a fmt.Stringer whose text needs HTML escaping in JSON.
+type stringer struct{}
+
+func (stringer) String() string { return "a<b" }
+
+// encoded returns the length of the encoding limits sizes records for.
+func encoded(t *testing.T, r record.Record) int {
+	t.Helper()
+	b, err := encoder.NewJSON(&encoder.Options{FlatErrors: true}).Append(nil, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(b)
+}
+
+func TestSizeOf(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 600, time.UTC)
+	tests := []struct {
+		name string
+		r    record.Record
+	}{
+		{"empty", record.Record{}},
+		{"message", record.Record{Level: level.Info, Message: "hello"}},
+		{"escapes", record.Record{Message: "q\"b\\n\n\r\t\x01<>&"}},
+		{"invalid utf-8", record.Record{Message: strings.Repeat("\xff", 200)}},
+		{"line separators", record.Record{Message: "a\u2028b\u2029c"}},
+		{"multibyte", record.Record{Message: "héllo, 世界"}},
+		{"time", record.Record{Time: now, Message: "m"}},
+		{"caller", record.Record{Caller: record.Frame{Function: "main.run", File: "/src/app/main.go", Line: 42}}},
+		{"context", record.Record{Ctx: dlogctx.Pack{Service: "api", Operation: "get", TraceID: "abc"}}},
+		{"error", record.Record{Err: errors.New("bad \"input\"\n\xff")}},
+		{"stack", record.Record{Stack: []record.Frame{{Function: "f", File: "a<b>.go", Line: 1}, {}}}},
+		{"fields", record.Record{Fields: []field.Field{
+			field.New("s", "x"),
+			field.New("i", -12345),
+			field.New("u", uint8(7)),
+			field.New("f", 1.5e300),
+			field.New("zero", 0.0),
+			field.New("b", true),
+			field.New("nil", nil),
+			field.New("d", 90*time.Minute),
+			field.New("t", now),
+			field.New("bytes", []byte("abc")),
+			field.New("strings", []string{"<a>", "b"}),
+			field.New("map", map[string]any{"k": "<&>", "d": time.Hour, "l": []any{1, "x\xff", nil}}),
+			field.New("err", errors.New("e")),
+			field.New("stringer", stringer{}),
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sz := sizeOf(tt.r)
+			got, want := sz.total(len(tt.r.Fields)), encoded(t, tt.r)
+			if got < want {
+				t.Errorf("sizeOf = %d, below the encoded size %d", got, want)
+			}
+			// Only time stamps and callers are overestimated noticeably.
+			if got > want+len(time.RFC3339Nano) {
+				t.Errorf("sizeOf = %d, far above the encoded size %d", got, want)
+			}
+		})
+	}
+}
+
+func TestRuneLen(t *testing.T) {
+	tests := []struct {
+		s          string
+		html       bool
+		width, len int
+	}{
+		{"a", false, 1, 1},
+		{"\"", false, 2, 1},
+		{"\n", false, 2, 1},
+		{"\x01", false, 6, 1},
+		{"<", false, 1, 1},
+		{"<", true, 6, 1},
+		{"é", false, 2, 2},
+		{"世", false, 3, 3},
+		{"\xff", false, 3, 1},
+		{"\u2028", false, 6, 3},
+		{"\u2029", true, 6, 3},
+	}
+	for _, tt := range tests {
+		w, n := runeLen(tt.s, tt.html)
+		if w != tt.width || n != tt.len {
+			t.Errorf("runeLen(%q, %v) = %d, %d, want %d, %d", tt.s, tt.html, w, n, tt.width, tt.len)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"dirpx.dev/dlog/apis/level"
+)
+
+// bucket is a token bucket. It is not synchronized; callers hold the lock
+// of the structure that owns it.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills b for the time elapsed since its last use and consumes one
+// token if available.
+func (b *bucket) take(now time.Time, l Limit) bool {
+	if b.last.IsZero() {
+		b.tokens = float64(l.Burst)
+	} else if dt := now.Sub(b.last).Seconds(); dt > 0 {
+		b.tokens = min(float64(l.Burst), b.tokens+dt*l.Rate)
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refund returns a token consumed by take.
+func (b *bucket) refund(l Limit) {
+	b.tokens = min(float64(l.Burst), b.tokens+1)
+}
+
+// bucketKey identifies a per-key bucket. Records of levels without their
+// own budget share the bucket with level -1.
+type bucketKey struct {
+	key   string
+	level level.Level
+}
+
+// entry is a per-key bucket in an lru.
+type entry struct {
+	k       bucketKey
+	b       bucket
+	limited uint64
+	elem    *list.Element
+}
+
+// lru is a size-bounded, mutex-guarded set of per-key buckets.
+type lru struct {
+	mu    sync.Mutex
+	max   int
+	m     map[bucketKey]*entry
+	order list.List // of *entry, most recently used first
+}
+
+// take consumes a token from the bucket of k, creating it if needed and
+// evicting the least recently used bucket when full.
+func (c *lru) take(k bucketKey, now time.Time, l Limit) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := c.m[k]
+	if e == nil {
+		if c.order.Len() >= c.max {
+			old := c.order.Back().Value.(*entry)
+			c.order.Remove(old.elem)
+			delete(c.m, old.k)
+		}
+		e = &entry{k: k}
+		e.elem = c.order.PushFront(e)
+		c.m[k] = e
+	} else {
+		c.order.MoveToFront(e.elem)
+	}
+	if e.b.take(now, l) {
+		return true
+	}
+	e.limited++
+	return false
+}
+
+// refund returns the token taken from the bucket of k, if it still exists.
+func (c *lru) refund(k bucketKey, l Limit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e := c.m[k]; e != nil {
+		e.b.refund(l)
+	}
+}
+
+// drain calls fn for every key limited since the last drain and resets
+// the counts.
+func (c *lru) drain(fn func(key string, n uint64)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.m {
+		if e.limited > 0 {
+			fn(e.k.key, e.limited)
+			e.limited = 0
+		}
+	}
+}
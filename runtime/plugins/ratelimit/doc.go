@@ -0,0 +1,50 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package ratelimit implements the "rate_limit" plugin: token-bucket rate
+// limiting of records, globally and per key.
+//
+// The key of a record is computed from Config.Key, a comma-separated list
+// of terms:
+//
+//   - ctx.<name>: a Pack attribute by canonical field name, JSON name or
+//     registered custom attribute name (ctx.service, ctx.op,
+//     ctx.operation, ctx.tenant_id, ...); unknown names are rejected;
+//   - field.<key>: the value of the last record field with that key.
+//
+// For example "ctx.service,field.tenant" keys buckets by service and
+// tenant. Records whose terms are all missing share the empty key.
+//
+// Three kinds of budgets apply, each a token bucket with a Rate in records
+// per second and a Burst:
+//
+//   - Global: one bucket shared by all records;
+//   - PerKey: one bucket per key;
+//   - Levels: per-level budgets that replace PerKey for records of that
+//     level, with one bucket per (key, level).
+//
+// A record passes only if every budget that applies to it has a token; a
+// record rejected by the global budget gets its per-key token back.
+// Records at or above Exempt are never limited. Per-key buckets are kept
+// in LRU caches bounded by MaxKeys; an evicted key starts again with a
+// full bucket.
+//
+// Every NoticeInterval in which records were limited, the stage emits a
+// warn-level "rate limited N records" notice through the stage.Emitter
+// bound by the pipeline (see stage.Deferrer). The notice carries the
+// total in fields.Suppressed and the counts of the most limited keys in
+// KeysField.
+package ratelimit
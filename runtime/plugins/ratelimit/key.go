@@ -0,0 +1,108 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// keySep joins the values of multi-term keys.
+const keySep = "\x1f"
+
+// term extracts one component of a record key.
+type term struct {
+	ctx  bool
+	name string
+}
+
+// keyExpr is a compiled Config.Key.
+type keyExpr []term
+
+// parseKey compiles a key expression. Context terms must name a fixed Pack
+// attribute, by canonical or JSON name, or a registered custom attribute.
+func parseKey(s string) (keyExpr, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var out keyExpr
+	for _, raw := range strings.Split(s, ",") {
+		t := strings.TrimSpace(raw)
+		src, name, ok := strings.Cut(t, ".")
+		if !ok || name == "" || (src != "ctx" && src != "field") {
+			return nil, fmt.Errorf("%w: key term %q must be ctx.<name> or field.<key>", ErrConfigInvalid, t)
+		}
+		if src == "ctx" {
+			canon, ok := dlogctx.ResolveAttr(name)
+			if !ok {
+				return nil, fmt.Errorf("%w: key term %q: unknown context attribute %q", ErrConfigInvalid, t, name)
+			}
+			name = canon
+		}
+		out = append(out, term{ctx: src == "ctx", name: name})
+	}
+	return out, nil
+}
+
+// eval returns the key of r.
+func (e keyExpr) eval(r record.Record) string {
+	switch len(e) {
+	case 0:
+		return ""
+	case 1:
+		return e[0].eval(r)
+	}
+	var b strings.Builder
+	for i, t := range e {
+		if i > 0 {
+			b.WriteString(keySep)
+		}
+		b.WriteString(t.eval(r))
+	}
+	return b.String()
+}
+
+// eval returns the textual value of t in r, or "" if it is missing.
+func (t term) eval(r record.Record) string {
+	var v any
+	if t.ctx {
+		var ok bool
+		if v, ok = r.Ctx.Lookup(t.name); !ok {
+			return ""
+		}
+	} else {
+		for i := len(r.Fields) - 1; i >= 0; i-- {
+			if r.Fields[i].Key == t.name {
+				v = r.Fields[i].Value
+				break
+			}
+		}
+	}
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return fmt.Sprint(x)
+	}
+}
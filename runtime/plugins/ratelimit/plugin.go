@@ -0,0 +1,313 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "rate_limit"
+
+// KeysField is the notice field holding the most limited keys and their
+// counts as a map[string]uint64.
+const KeysField = "rate_limited_keys"
+
+// Defaults applied to zero Config values.
+const (
+	DefaultMaxKeys        = 10000
+	DefaultNoticeInterval = 10 * time.Second
+)
+
+// noticeKeys is the maximum number of keys listed in a notice.
+const noticeKeys = 10
+
+// shards is the number of LRU shards; a power of two.
+const shards = 16
+
+var (
+	// ErrConfigInvalid is returned when a rate_limit configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid rate_limit config")
+)
+
+// Limit is a token-bucket budget.
+type Limit struct {
+	// Rate is the sustained number of records per second; must be > 0.
+	Rate float64 `json:"rate" yaml:"rate"`
+
+	// Burst is the bucket size; default (and minimum) 1.
+	Burst int `json:"burst,omitempty" yaml:"burst,omitempty"`
+}
+
+// Config is the configuration payload of the "rate_limit" plugin.
+type Config struct {
+	// Key is the bucket key expression (see the package documentation).
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// Global limits all records together.
+	Global *Limit `json:"global,omitempty" yaml:"global,omitempty"`
+
+	// PerKey limits records per key.
+	PerKey *Limit `json:"per_key,omitempty" yaml:"per_key,omitempty"`
+
+	// Levels maps level names to per-key budgets that replace PerKey for
+	// records of that level.
+	Levels map[string]Limit `json:"levels,omitempty" yaml:"levels,omitempty"`
+
+	// Exempt is the lowest level that is never limited; nil limits all levels.
+	Exempt *level.Level `json:"exempt,omitempty" yaml:"exempt,omitempty"`
+
+	// MaxKeys bounds the number of per-key buckets; default DefaultMaxKeys.
+	MaxKeys int `json:"max_keys,omitempty" yaml:"max_keys,omitempty"`
+
+	// NoticeInterval is the minimum time between notices as a
+	// time.ParseDuration string; default DefaultNoticeInterval.
+	NoticeInterval string `json:"notice_interval,omitempty" yaml:"notice_interval,omitempty"`
+}
+
+// Builder builds "rate_limit" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "rate_limit".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "rate_limit" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name    string
+	enabled bool
+	key     keyExpr
+	global  *Limit
+	perKey  *Limit
+	levels  [level.Fatal + 1]*Limit
+	exempt  level.Level
+	notice  time.Duration
+	now     func() time.Time
+
+	globalMu sync.Mutex
+	globalB  bucket
+
+	seed   maphash.Seed
+	shards [shards]lru
+
+	limited    atomic.Uint64
+	nextNotice atomic.Int64
+	emit       atomic.Pointer[stage.Emitter]
+}
+
+var (
+	_ plugin.RateLimiter = (*Stage)(nil)
+	_ stage.Deferrer     = (*Stage)(nil)
+)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{
+		name:    name,
+		enabled: enabled,
+		exempt:  level.Fatal + 1,
+		notice:  DefaultNoticeInterval,
+		now:     time.Now,
+		seed:    maphash.MakeSeed(),
+	}
+	var err error
+	if s.key, err = parseKey(cfg.Key); err != nil {
+		return nil, err
+	}
+	if s.global, err = checkLimit("global", cfg.Global); err != nil {
+		return nil, err
+	}
+	if s.perKey, err = checkLimit("per_key", cfg.PerKey); err != nil {
+		return nil, err
+	}
+	for name, l := range cfg.Levels {
+		lvl, err := level.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+		}
+		if s.levels[lvl], err = checkLimit("levels."+name, &l); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Exempt != nil {
+		s.exempt = *cfg.Exempt
+	}
+	if cfg.NoticeInterval != "" {
+		d, err := time.ParseDuration(cfg.NoticeInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: notice_interval %q must be a positive duration", ErrConfigInvalid, cfg.NoticeInterval)
+		}
+		s.notice = d
+	}
+	maxKeys := DefaultMaxKeys
+	switch {
+	case cfg.MaxKeys < 0:
+		return nil, fmt.Errorf("%w: max_keys must not be negative", ErrConfigInvalid)
+	case cfg.MaxKeys > 0:
+		maxKeys = cfg.MaxKeys
+	}
+	for i := range s.shards {
+		s.shards[i].max = max(1, (maxKeys+shards-1)/shards)
+		s.shards[i].m = make(map[bucketKey]*entry)
+	}
+	return s, nil
+}
+
+// Bind sets the Emitter used for notices.
+func (s *Stage) Bind(e stage.Emitter) { s.emit.Store(&e) }
+
+// Process drops r if any budget that applies to it is exhausted.
+func (s *Stage) Process(ctx context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	if r.Level >= s.exempt {
+		return r, stage.Continue, nil
+	}
+	now := s.now()
+	if next := s.nextNotice.Load(); now.UnixNano() >= next && s.nextNotice.CompareAndSwap(next, now.Add(s.notice).UnixNano()) {
+		// The notice is not r: a failure to deliver it is the pipeline's
+		// to account for (see stage.Emitter).
+		_ = s.sendNotice(ctx, now, r.Ctx)
+	}
+	if !s.allow(r, now) {
+		s.limited.Add(1)
+		return r, stage.Drop, nil
+	}
+	return r, stage.Continue, nil
+}
+
+// Flush emits a notice if records were limited since the last one.
+func (s *Stage) Flush(ctx context.Context) error {
+	now := s.now()
+	s.nextNotice.Store(now.Add(s.notice).UnixNano())
+	return s.sendNotice(ctx, now, dlogctx.Pack{})
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// allow reports whether the budgets of r have tokens and consumes them.
+// A record rejected by the global budget does not spend its per-key token.
+func (s *Stage) allow(r record.Record, now time.Time) bool {
+	l, bk := s.perKey, bucketKey{level: -1}
+	if r.Level >= 0 && int(r.Level) < len(s.levels) && s.levels[r.Level] != nil {
+		l, bk.level = s.levels[r.Level], r.Level
+	}
+	var sh *lru
+	if l != nil {
+		bk.key = s.key.eval(r)
+		sh = &s.shards[maphash.String(s.seed, bk.key)&(shards-1)]
+		if !sh.take(bk, now, *l) {
+			return false
+		}
+	}
+	if s.global != nil {
+		s.globalMu.Lock()
+		ok := s.globalB.take(now, *s.global)
+		s.globalMu.Unlock()
+		if !ok {
+			if sh != nil {
+				sh.refund(bk, *l)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// sendNotice emits the notice for records limited since the last one, if
+// any. The notice inherits the identity part of pack.
+func (s *Stage) sendNotice(ctx context.Context, now time.Time, pack dlogctx.Pack) error {
+	n := s.limited.Swap(0)
+	type keyCount struct {
+		key string
+		n   uint64
+	}
+	var top []keyCount
+	for i := range s.shards {
+		s.shards[i].drain(func(key string, n uint64) {
+			top = append(top, keyCount{key, n})
+		})
+	}
+	if n == 0 {
+		return nil
+	}
+	e := s.emit.Load()
+	if e == nil {
+		return nil
+	}
+
+	fs := []field.Field{field.New(fields.Suppressed, n)}
+	if len(top) > 0 {
+		slices.SortFunc(top, func(a, b keyCount) int {
+			return cmp.Or(cmp.Compare(b.n, a.n), cmp.Compare(a.key, b.key))
+		})
+		m := make(map[string]uint64, min(len(top), noticeKeys))
+		for _, kc := range top[:min(len(top), noticeKeys)] {
+			m[kc.key] += kc.n
+		}
+		fs = append(fs, field.New(KeysField, m))
+	}
+	p := pack.Identity()
+	p.Component, p.Subsystem, p.Operation = "", "", ""
+	msg := fmt.Sprintf("rate limited %d records", n)
+	return (*e).Emit(ctx, record.NewRecord(now, level.Warn, msg, p, fs, nil))
+}
+
+// checkLimit validates an optional budget and applies the Burst default.
+func checkLimit(name string, l *Limit) (*Limit, error) {
+	if l == nil {
+		return nil, nil
+	}
+	if !(l.Rate > 0) {
+		return nil, fmt.Errorf("%w: %s rate must be positive", ErrConfigInvalid, name)
+	}
+	if l.Burst < 0 {
+		return nil, fmt.Errorf("%w: %s burst must not be negative", ErrConfigInvalid, name)
+	}
+	out := *l
+	out.Burst = max(1, out.Burst)
+	return &out, nil
+}
@@ -0,0 +1,271 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Built-in detector names accepted as keys of Config.Detectors.
+const (
+	DetectorEmail        = "email"
+	DetectorPhone        = "phone"
+	DetectorIPv4         = "ipv4"
+	DetectorIPv6         = "ipv6"
+	DetectorCreditCard   = "credit_card"
+	DetectorIBAN         = "iban"
+	DetectorJWT          = "jwt"
+	DetectorAWSAccessKey = "aws_access_key"
+	DetectorGitHubToken  = "github_token"
+	DetectorPrivateKey   = "private_key"
+)
+
+// DetectorConfig toggles and tunes a built-in detector.
+type DetectorConfig struct {
+	// Enabled turns the detector on or off; if omitted, a listed detector
+	// is enabled.
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+
+	// Strategy, Mask and Keep have the same meaning as in RuleConfig and
+	// are applied to every detected value.
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	Mask     string `json:"mask,omitempty" yaml:"mask,omitempty"`
+	Keep     int    `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// builtin describes a detector: a cheap prefilter that rejects most
+// strings without running the regular expression, the expression itself
+// and an optional validator for candidate matches.
+type builtin struct {
+	prefilter func(s string) bool
+	re        *regexp.Regexp
+	valid     func(match string) bool
+}
+
+// detectorOrder is the evaluation order. Multi-line and structured
+// secrets go first so that their bodies are not partially matched by the
+// more generic detectors.
+var detectorOrder = []string{
+	DetectorPrivateKey,
+	DetectorJWT,
+	DetectorGitHubToken,
+	DetectorAWSAccessKey,
+	DetectorEmail,
+	DetectorCreditCard,
+	DetectorIBAN,
+	DetectorIPv6,
+	DetectorIPv4,
+	DetectorPhone,
+}
+
+var builtins = map[string]builtin{
+	DetectorPrivateKey: {
+		prefilter: func(s string) bool { return strings.Contains(s, "-----BEGIN") },
+		re:        regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY-----[\s\S]*?-----END (?:[A-Z0-9]+ )*PRIVATE KEY-----`),
+	},
+	DetectorJWT: {
+		prefilter: func(s string) bool { return strings.Contains(s, "eyJ") },
+		re:        regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	},
+	DetectorGitHubToken: {
+		prefilter: func(s string) bool { return strings.Contains(s, "gh") || strings.Contains(s, "github_pat_") },
+		re:        regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{22,255})\b`),
+	},
+	DetectorAWSAccessKey: {
+		prefilter: func(s string) bool { return strings.Contains(s, "AKIA") || strings.Contains(s, "ASIA") },
+		re:        regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),
+	},
+	DetectorEmail: {
+		prefilter: func(s string) bool { return strings.IndexByte(s, '@') >= 0 },
+		re:        regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	},
+	DetectorCreditCard: {
+		prefilter: func(s string) bool { return countDigits(s) >= 13 },
+		re:        regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid:     validCard,
+	},
+	DetectorIBAN: {
+		prefilter: func(s string) bool { return countDigits(s) >= 4 },
+		re:        regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+		valid:     validIBAN,
+	},
+	DetectorIPv6: {
+		prefilter: func(s string) bool { return strings.Count(s, ":") >= 2 },
+		re:        regexp.MustCompile(`(?i)(?:[0-9a-f]{1,4}:|:)(?:[0-9a-f]{0,4}:){1,7}[0-9a-f]{0,4}`),
+		valid: func(m string) bool {
+			a, err := netip.ParseAddr(m)
+			return err == nil && a.Is6()
+		},
+	},
+	DetectorIPv4: {
+		prefilter: func(s string) bool { return strings.Count(s, ".") >= 3 },
+		re:        regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		valid: func(m string) bool {
+			a, err := netip.ParseAddr(m)
+			return err == nil && a.Is4()
+		},
+	},
+	DetectorPhone: {
+		prefilter: func(s string) bool { return countDigits(s) >= 10 },
+		re:        regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?\(?\d{2,4}\)?[ .-]?\d{3,4}[ .-]?\d{3,4}\b`),
+		valid:     validPhone,
+	},
+}
+
+// DetectorNames returns the names of all built-in detectors, sorted.
+func DetectorNames() []string {
+	out := make([]string, 0, len(builtins))
+	for name := range builtins {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// detector is an enabled, configured built-in detector.
+type detector struct {
+	name  string
+	b     builtin
+	strat strategy
+	// hits counts redacted matches; shared by all copies of the detector.
+	hits *atomic.Uint64
+}
+
+// compileDetectors validates the detector configuration and returns the
+// enabled detectors in evaluation order.
+func compileDetectors(cfg map[string]DetectorConfig, key []byte) ([]detector, error) {
+	for name := range cfg {
+		if _, ok := builtins[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown detector %q (known: %s)",
+				ErrConfigInvalid, name, strings.Join(DetectorNames(), ", "))
+		}
+	}
+	var out []detector
+	for _, name := range detectorOrder {
+		dc, ok := cfg[name]
+		if !ok || (dc.Enabled != nil && !*dc.Enabled) {
+			continue
+		}
+		st, err := newStrategy(dc.Strategy, dc.Mask, dc.Keep, key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: detector %q: %v", ErrConfigInvalid, name, err)
+		}
+		out = append(out, detector{name: name, b: builtins[name], strat: st, hits: new(atomic.Uint64)})
+	}
+	return out, nil
+}
+
+// apply redacts every valid match in s.
+func (d detector) apply(s string) (string, bool) {
+	if !d.b.prefilter(s) {
+		return s, false
+	}
+	changed := false
+	out := d.b.re.ReplaceAllStringFunc(s, func(m string) string {
+		if d.b.valid != nil && !d.b.valid(m) {
+			return m
+		}
+		changed = true
+		d.hits.Add(1)
+		return d.strat.text(m)
+	})
+	return out, changed
+}
+
+// countDigits returns the number of ASCII digits in s.
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// digitsOf returns the ASCII digits of s.
+func digitsOf(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			out = append(out, s[i])
+		}
+	}
+	return out
+}
+
+// validCard reports whether m has 13-19 digits passing the Luhn check.
+func validCard(m string) bool {
+	d := digitsOf(m)
+	if len(d) < 13 || len(d) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(d) - 1; i >= 0; i-- {
+		n := int(d[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN reports whether m passes the ISO 13616 mod-97 check.
+func validIBAN(m string) bool {
+	s := strings.ReplaceAll(m, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	s = s[4:] + s[:4]
+	// Letters expand to two digits (A=10 ... Z=35); the remainder is
+	// computed incrementally to avoid big integers.
+	rem := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+// validPhone accepts 10-15 digit numbers that are written like phone
+// numbers: with a leading "+" or with separators. Bare digit runs are
+// rejected because they are usually IDs or timestamps.
+func validPhone(m string) bool {
+	n := countDigits(m)
+	if n < 10 || n > 15 {
+		return false
+	}
+	return strings.HasPrefix(m, "+") || strings.ContainsAny(m, " .-()")
+}
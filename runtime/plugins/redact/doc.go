@@ -0,0 +1,62 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package redact implements the "redact" plugin, which masks or removes
+// sensitive data before a record is encoded.
+//
+// A configuration is an ordered list of rules. Each rule selects data in
+// one or more ways:
+//
+//   - Keys: globs matched case-insensitively against field keys, at the
+//     top level and inside nested map[string]any values
+//     (e.g. "password", "*_token");
+//   - Paths: dot-separated paths into map or struct field values
+//     (e.g. "user.credentials.secret", "headers.Authorization"); each
+//     segment may be a glob;
+//   - Patterns: regular expressions applied to the Message, to string
+//     field values and to the text of Err.
+//
+// and applies one strategy to what it selected:
+//
+//   - drop: remove the field (or the matched text);
+//   - mask: replace with a fixed string (default "***");
+//   - last: mask everything except the last N characters;
+//   - hash: replace with a keyed HMAC-SHA256 digest, so equal values can
+//     still be correlated without being revealed.
+//
+// Err is projected with record.DefaultErrorAdapter, so the fields an
+// error reports through field.Fielder and the messages of the errors it
+// wraps are redacted like record fields and text. A redacted error is
+// replaced by a *record.AdaptedError that encoders render as is.
+//
+// Rules and detectors reach field values at any depth: strings, nested
+// maps, []any and []string elements, and integers of 13 or more digits
+// (so that a card number logged as a number is caught; a redacted number
+// becomes a string). Custom Pack attributes are redacted the same way; an
+// attribute whose redacted value no longer fits its type is removed.
+//
+// Beyond explicit rules, built-in detectors find sensitive values anywhere
+// in the text: emails, phone numbers, IPv4/IPv6 addresses, credit card
+// numbers (Luhn-validated), IBANs (mod-97-validated), JWTs, AWS access
+// keys, GitHub tokens and PEM private key blocks. Each detector is enabled
+// and tuned individually in Config.Detectors, guards its regular
+// expression with a cheap prefilter so that ordinary text costs a few
+// byte scans, and counts its hits (see Stage.DetectorHits).
+//
+// For key and path rules the first matching rule wins. The stage is
+// copy-on-write: it never modifies the caller's field slice or maps;
+// structs reached by a path are converted into map[string]any.
+package redact
@@ -0,0 +1,86 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"context"
+
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "redact"
+
+// Builder builds "redact" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "redact".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and compiles its rules.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "redact" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name    string
+	enabled bool
+	rd      *redactor
+}
+
+var _ plugin.Redactor = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	rd, err := compile(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Stage{name: name, enabled: enabled, rd: rd}, nil
+}
+
+// Process returns a redacted copy of r. It never drops records.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	return s.rd.record(r), stage.Continue, nil
+}
+
+// DetectorHits returns the number of values redacted so far by each
+// enabled built-in detector, keyed by detector name. Runtimes export these
+// counters as metrics.
+func (s *Stage) DetectorHits() map[string]uint64 {
+	out := make(map[string]uint64, len(s.rd.detectors))
+	for _, d := range s.rd.detectors {
+		out[d.name] = d.hits.Load()
+	}
+	return out
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
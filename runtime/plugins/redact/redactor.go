@@ -0,0 +1,493 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/record"
+)
+
+var (
+	// ErrConfigInvalid is returned when a redact configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid redact config")
+)
+
+// Config is the configuration payload of the "redact" plugin.
+type Config struct {
+	// Rules are evaluated in order.
+	Rules []RuleConfig `json:"rules" yaml:"rules"`
+
+	// HMACKey is the secret used by the hash strategy.
+	HMACKey string `json:"hmac_key,omitempty" yaml:"hmac_key,omitempty"`
+
+	// HMACKeyEnv names an environment variable holding the HMAC key, so the
+	// secret does not have to live in the configuration. It is consulted
+	// when HMACKey is empty.
+	HMACKeyEnv string `json:"hmac_key_env,omitempty" yaml:"hmac_key_env,omitempty"`
+
+	// Detectors enables built-in PII/secret detectors by name (see
+	// DetectorNames). Detectors run on the same text as Patterns, after
+	// the pattern rules.
+	Detectors map[string]DetectorConfig `json:"detectors,omitempty" yaml:"detectors,omitempty"`
+}
+
+// RuleConfig describes one redaction rule.
+type RuleConfig struct {
+	// Keys are field key globs (path.Match syntax, case-insensitive).
+	Keys []string `json:"keys,omitempty" yaml:"keys,omitempty"`
+
+	// Paths are dot-separated paths into map/struct field values.
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+
+	// Patterns are regular expressions applied to text.
+	Patterns []string `json:"patterns,omitempty" yaml:"patterns,omitempty"`
+
+	// Strategy is one of "drop", "mask" (default), "last" or "hash".
+	Strategy string `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+
+	// Mask is the replacement text for mask and last; default DefaultMask.
+	Mask string `json:"mask,omitempty" yaml:"mask,omitempty"`
+
+	// Keep is the number of trailing characters kept by the last strategy.
+	Keep int `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// keyRule redacts fields whose key matches glob.
+type keyRule struct {
+	glob  string
+	strat strategy
+}
+
+// pathRule redacts the value found at segs.
+type pathRule struct {
+	segs  []string
+	strat strategy
+}
+
+// patternRule redacts regular expression matches in text.
+type patternRule struct {
+	re    *regexp.Regexp
+	strat strategy
+}
+
+// redactor is a compiled, immutable Config.
+type redactor struct {
+	keys      []keyRule
+	paths     []pathRule
+	patterns  []patternRule
+	detectors []detector
+}
+
+// compile validates cfg and builds a redactor.
+func compile(cfg Config) (*redactor, error) {
+	key := []byte(cfg.HMACKey)
+	if len(key) == 0 && cfg.HMACKeyEnv != "" {
+		key = []byte(os.Getenv(cfg.HMACKeyEnv))
+	}
+
+	rd := &redactor{}
+	for i, rc := range cfg.Rules {
+		fail := func(format string, args ...any) error {
+			return fmt.Errorf("%w: rule %d: %s", ErrConfigInvalid, i, fmt.Sprintf(format, args...))
+		}
+		if len(rc.Keys)+len(rc.Paths)+len(rc.Patterns) == 0 {
+			return nil, fail("no keys, paths or patterns")
+		}
+		st, err := newStrategy(rc.Strategy, rc.Mask, rc.Keep, key)
+		if err != nil {
+			return nil, fail("%v", err)
+		}
+		for _, k := range rc.Keys {
+			k = strings.ToLower(k)
+			if _, err := path.Match(k, ""); err != nil || k == "" {
+				return nil, fail("key glob %q is invalid", k)
+			}
+			rd.keys = append(rd.keys, keyRule{glob: k, strat: st})
+		}
+		for _, p := range rc.Paths {
+			segs := strings.Split(p, ".")
+			for _, s := range segs {
+				if _, err := path.Match(s, ""); err != nil || s == "" {
+					return nil, fail("path %q is invalid", p)
+				}
+			}
+			rd.paths = append(rd.paths, pathRule{segs: segs, strat: st})
+		}
+		for _, p := range rc.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fail("pattern %q: %v", p, err)
+			}
+			rd.patterns = append(rd.patterns, patternRule{re: re, strat: st})
+		}
+	}
+	ds, err := compileDetectors(cfg.Detectors, key)
+	if err != nil {
+		return nil, err
+	}
+	rd.detectors = ds
+	return rd, nil
+}
+
+// record redacts r and returns the result. r and its field slice are
+// never modified.
+func (rd *redactor) record(r record.Record) record.Record {
+	if s, ok := rd.text(r.Message); ok {
+		r.Message = s
+	}
+	if r.Err != nil {
+		if info, ok := rd.errorInfo(record.DefaultErrorAdapter.Adapt(r.Err)); ok {
+			r.Err = &record.AdaptedError{Info: info}
+		}
+	}
+
+	if fs, ok := rd.fields(r.Fields); ok {
+		r.Fields = fs
+	}
+	if p, ok := rd.pack(r.Ctx); ok {
+		r.Ctx = p
+	}
+	return r
+}
+
+// fields redacts fs and reports whether anything changed. fs is never
+// modified.
+func (rd *redactor) fields(fs []field.Field) ([]field.Field, bool) {
+	var out []field.Field
+	for i, f := range fs {
+		v, keep, changed := rd.field(f.Key, f.Value, true)
+		if changed && out == nil {
+			out = make([]field.Field, i, len(fs))
+			copy(out, fs[:i])
+		}
+		if out != nil && keep {
+			out = append(out, field.New(f.Key, v))
+		}
+	}
+	return out, out != nil
+}
+
+// errorInfo redacts the messages and fields of an error projection and of
+// all its causes, so that values exposed through field.Fielder are
+// subject to the same rules as record fields.
+func (rd *redactor) errorInfo(info record.ErrorInfo) (record.ErrorInfo, bool) {
+	changed := false
+	if s, ok := rd.text(info.Message); ok {
+		info.Message, changed = s, true
+	}
+	if fs, ok := rd.fields(info.Fields); ok {
+		info.Fields, changed = fs, true
+	}
+	var causes []record.ErrorInfo
+	for i, c := range info.Causes {
+		nc, ok := rd.errorInfo(c)
+		if !ok {
+			continue
+		}
+		if causes == nil {
+			causes = append([]record.ErrorInfo(nil), info.Causes...)
+		}
+		causes[i] = nc
+	}
+	if causes != nil {
+		info.Causes, changed = causes, true
+	}
+	return info, changed
+}
+
+// field redacts a single key/value pair. Path rules are anchored at the
+// top level, so they are only consulted when top is set.
+func (rd *redactor) field(key string, value any, top bool) (v any, keep, changed bool) {
+	if st, ok := rd.matchKey(key); ok {
+		v, keep = st.value(value)
+		return v, keep, true
+	}
+
+	v = value
+	if top {
+		for _, p := range rd.paths {
+			if !segMatch(p.segs[0], key) {
+				continue
+			}
+			if len(p.segs) == 1 {
+				if v, keep = p.strat.value(v); !keep {
+					return nil, false, true
+				}
+				changed = true
+				continue
+			}
+			if nv, ok := redactPath(v, p.segs[1:], p.strat); ok {
+				v, changed = nv, true
+			}
+		}
+	}
+	if nv, ok := rd.walk(v); ok {
+		v, changed = nv, true
+	}
+	return v, true, changed
+}
+
+// walk applies key rules inside nested maps and pattern rules and
+// detectors to strings, slice elements and long integers.
+func (rd *redactor) walk(v any) (any, bool) {
+	switch x := v.(type) {
+	case string:
+		return rd.text(x)
+	case []string:
+		var out []string
+		for i, e := range x {
+			ne, ok := rd.text(e)
+			if !ok {
+				continue
+			}
+			if out == nil {
+				out = append([]string(nil), x...)
+			}
+			out[i] = ne
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []any:
+		var out []any
+		for i, e := range x {
+			ne, ok := rd.walk(e)
+			if !ok {
+				continue
+			}
+			if out == nil {
+				out = append([]any(nil), x...)
+			}
+			out[i] = ne
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case int, int64, uint, uint64:
+		return rd.number(x)
+	case map[string]any:
+		var out map[string]any
+		for k, mv := range x {
+			nv, keep, changed := rd.field(k, mv, false)
+			if !changed {
+				continue
+			}
+			if out == nil {
+				out = cloneMap(x)
+			}
+			if keep {
+				out[k] = nv
+			} else {
+				delete(out, k)
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	default:
+		return v, false
+	}
+}
+
+// minNumberDigits is the length of the shortest card number; shorter
+// integers are not inspected.
+const minNumberDigits = 13
+
+// number applies pattern rules and detectors to the decimal text of an
+// integer with at least minNumberDigits digits, such as a card number
+// logged as a number. A redacted number becomes a string.
+func (rd *redactor) number(v any) (any, bool) {
+	var t string
+	switch x := v.(type) {
+	case int:
+		t = strconv.FormatInt(int64(x), 10)
+	case int64:
+		t = strconv.FormatInt(x, 10)
+	case uint:
+		t = strconv.FormatUint(uint64(x), 10)
+	case uint64:
+		t = strconv.FormatUint(x, 10)
+	}
+	if len(strings.TrimPrefix(t, "-")) < minNumberDigits {
+		return v, false
+	}
+	if s, ok := rd.text(t); ok {
+		return s, true
+	}
+	return v, false
+}
+
+// pack redacts the custom attributes of p. A redacted value that no
+// longer fits its attribute's type, or that is dropped, removes the
+// attribute.
+func (rd *redactor) pack(p dlogctx.Pack) (dlogctx.Pack, bool) {
+	type change struct {
+		name  string
+		value any
+		keep  bool
+	}
+	var changes []change
+	p.RangeAttrs(func(name string, value any) bool {
+		if v, keep, changed := rd.field(name, value, false); changed {
+			changes = append(changes, change{name, v, keep})
+		}
+		return true
+	})
+	for _, c := range changes {
+		if c.keep {
+			if np, err := p.Set(c.name, c.value); err == nil {
+				p = np
+				continue
+			}
+		}
+		p = p.Delete(c.name)
+	}
+	return p, len(changes) > 0
+}
+
+// matchKey returns the strategy of the first key rule matching key.
+func (rd *redactor) matchKey(key string) (strategy, bool) {
+	if len(rd.keys) == 0 {
+		return strategy{}, false
+	}
+	lk := strings.ToLower(key)
+	for _, k := range rd.keys {
+		if ok, _ := path.Match(k.glob, lk); ok {
+			return k.strat, true
+		}
+	}
+	return strategy{}, false
+}
+
+// text applies all pattern rules and detectors to s.
+func (rd *redactor) text(s string) (string, bool) {
+	changed := false
+	for _, p := range rd.patterns {
+		if !p.re.MatchString(s) {
+			continue
+		}
+		s = p.re.ReplaceAllStringFunc(s, p.strat.text)
+		changed = true
+	}
+	for _, d := range rd.detectors {
+		var ok bool
+		if s, ok = d.apply(s); ok {
+			changed = true
+		}
+	}
+	return s, changed
+}
+
+// redactPath applies st to the value found at segs (non-empty) inside v.
+// Maps are cloned and structs converted to maps before modification.
+func redactPath(v any, segs []string, st strategy) (any, bool) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		if m, ok = structMap(v); !ok {
+			return v, false
+		}
+	} else {
+		m = cloneMap(m)
+	}
+
+	changed := false
+	for k, mv := range m {
+		if !segMatch(segs[0], k) {
+			continue
+		}
+		if len(segs) == 1 {
+			if nv, keep := st.value(mv); keep {
+				m[k] = nv
+			} else {
+				delete(m, k)
+			}
+			changed = true
+			continue
+		}
+		if nv, ok := redactPath(mv, segs[1:], st); ok {
+			m[k] = nv
+			changed = true
+		}
+	}
+	if !changed {
+		return v, false
+	}
+	return m, true
+}
+
+// segMatch matches a path segment glob against a key.
+func segMatch(glob, key string) bool {
+	ok, _ := path.Match(glob, key)
+	return ok
+}
+
+// cloneMap returns a shallow copy of m.
+func cloneMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// structMap converts a struct (or pointer to struct) into a map keyed by
+// the JSON names of its exported fields.
+func structMap(v any) (map[string]any, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	rt := rv.Type()
+	out := make(map[string]any, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Name
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			tn, _, _ := strings.Cut(tag, ",")
+			if tn == "-" {
+				continue
+			}
+			if tn != "" {
+				name = tn
+			}
+		}
+		out[name] = rv.Field(i).Interface()
+	}
+	return out, true
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Strategy names accepted in RuleConfig.Strategy.
+const (
+	StrategyDrop = "drop"
+	StrategyMask = "mask"
+	StrategyLast = "last"
+	StrategyHash = "hash"
+)
+
+// DefaultMask is the replacement used by the mask and last strategies
+// when RuleConfig.Mask is empty.
+const DefaultMask = "***"
+
+// strategy is a compiled redaction strategy.
+type strategy struct {
+	kind string
+	mask string
+	keep int
+	// key is the HMAC key for the hash strategy.
+	key []byte
+}
+
+// newStrategy validates and compiles a strategy.
+func newStrategy(kind, mask string, keep int, key []byte) (strategy, error) {
+	if mask == "" {
+		mask = DefaultMask
+	}
+	s := strategy{kind: kind, mask: mask, keep: keep, key: key}
+	switch kind {
+	case StrategyDrop, StrategyMask:
+	case StrategyLast:
+		if keep <= 0 {
+			return s, fmt.Errorf("strategy %q requires keep > 0", kind)
+		}
+	case StrategyHash:
+		if len(key) == 0 {
+			return s, fmt.Errorf("strategy %q requires an HMAC key", kind)
+		}
+	case "":
+		s.kind = StrategyMask
+	default:
+		return s, fmt.Errorf("unknown strategy %q", kind)
+	}
+	return s, nil
+}
+
+// value redacts a whole field value. It reports false when the field
+// must be removed.
+func (s strategy) value(v any) (any, bool) {
+	switch s.kind {
+	case StrategyDrop:
+		return nil, false
+	case StrategyMask:
+		return s.mask, true
+	default:
+		return s.text(stringOf(v)), true
+	}
+}
+
+// text redacts a piece of text: the whole of a string value or a single
+// regular expression match.
+func (s strategy) text(t string) string {
+	switch s.kind {
+	case StrategyDrop:
+		return ""
+	case StrategyMask:
+		return s.mask
+	case StrategyLast:
+		r := []rune(t)
+		if len(r) <= s.keep {
+			return s.mask
+		}
+		return s.mask + string(r[len(r)-s.keep:])
+	default:
+		m := hmac.New(sha256.New, s.key)
+		m.Write([]byte(t))
+		return "hmac:" + hex.EncodeToString(m.Sum(nil)[:16])
+	}
+}
+
+// stringOf renders a value for strategies that operate on text.
+func stringOf(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case []byte:
+		return string(x)
+	case fmt.Stringer:
+		return x.String()
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
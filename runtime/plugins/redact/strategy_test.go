@@ -0,0 +1,139 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+)
+
+func TestStrategies(t *testing.T) {
+	const secret = "correct horse battery staple"
+	tests := []struct {
+		name string
+		rule RuleConfig
+		// want is the redacted password; absent means the field is removed.
+		want   string
+		absent bool
+	}{
+		{name: "drop", rule: RuleConfig{Strategy: StrategyDrop}, absent: true},
+		{name: "mask", rule: RuleConfig{Strategy: StrategyMask}, want: DefaultMask},
+		{name: "mask/custom", rule: RuleConfig{Strategy: StrategyMask, Mask: "[hidden]"}, want: "[hidden]"},
+		{name: "mask/default", rule: RuleConfig{}, want: DefaultMask},
+		{name: "last", rule: RuleConfig{Strategy: StrategyLast, Keep: 4}, want: "***aple"},
+		{name: "last/custom", rule: RuleConfig{Strategy: StrategyLast, Keep: 3, Mask: "..."}, want: "...ple"},
+		{name: "hash", rule: RuleConfig{Strategy: StrategyHash}, want: "hmac:9f077dd7b15aa34d388c4768efdcfa81"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Keys = []string{"password"}
+			s, err := New("test", Config{Rules: []RuleConfig{tt.rule}, HMACKey: "test-key"}, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := record.Record{
+				Level:   level.Info,
+				Message: "login",
+				Fields:  []field.Field{field.New("user", "alice"), field.New("password", secret)},
+			}
+			out, _, err := s.Process(context.Background(), r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, ok := lookup(out, "password")
+			if tt.absent {
+				if ok {
+					t.Fatalf("password = %v, want it removed", got)
+				}
+				return
+			}
+			if !ok || got != tt.want {
+				t.Errorf("password = %v, want %q", got, tt.want)
+			}
+			if user, _ := lookup(out, "user"); user != "alice" {
+				t.Errorf("user = %v, want it untouched", user)
+			}
+		})
+	}
+}
+
+func TestStrategyText(t *testing.T) {
+	last, _ := newStrategy(StrategyLast, "", 4, nil)
+	tests := []struct {
+		in, want string
+	}{
+		{"4111111111111111", "***1111"},
+		{"1234", "***"},
+		{"abc", "***"},
+		{"€€€€€€", "***€€€€"},
+	}
+	for _, tt := range tests {
+		if got := last.text(tt.in); got != tt.want {
+			t.Errorf("last.text(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	a, _ := newStrategy(StrategyHash, "", 0, []byte("key-a"))
+	b, _ := newStrategy(StrategyHash, "", 0, []byte("key-b"))
+	h := a.text("alice@example.com")
+	if !strings.HasPrefix(h, "hmac:") || len(h) != len("hmac:")+32 {
+		t.Errorf("hash = %q, want hmac: and 32 hex digits", h)
+	}
+	if strings.Contains(h, "alice") {
+		t.Errorf("hash %q leaks the input", h)
+	}
+	if a.text("alice@example.com") != h {
+		t.Error("hash is not deterministic")
+	}
+	if a.text("bob@example.com") == h {
+		t.Error("different inputs hash to the same value")
+	}
+	if b.text("alice@example.com") == h {
+		t.Error("different keys hash to the same value")
+	}
+}
+
+func TestStrategyErrors(t *testing.T) {
+	tests := []struct {
+		kind string
+		keep int
+		key  []byte
+	}{
+		{kind: StrategyLast},
+		{kind: StrategyHash},
+		{kind: "rot13"},
+	}
+	for _, tt := range tests {
+		if _, err := newStrategy(tt.kind, "", tt.keep, tt.key); err == nil {
+			t.Errorf("newStrategy(%q) succeeded, want an error", tt.kind)
+		}
+	}
+}
+
+func lookup(r record.Record, key string) (any, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
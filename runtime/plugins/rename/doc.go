@@ -0,0 +1,42 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package rename implements the "rename" plugin, which normalizes field
+// keys during schema migrations and moves values between record fields and
+// Pack attributes.
+//
+// Operations run in this order:
+//
+//  1. Keys: explicit renames, e.g. {"userId": "user_id", "uid": "user_id"};
+//  2. Case: "snake" converts the remaining keys from camelCase, PascalCase
+//     or kebab-case to snake_case ("userID" -> "user_id"); Keys is
+//     consulted again with the converted key;
+//  3. Demote: Pack attributes copied out into fields and cleared from the
+//     Pack, e.g. {"tenant_id": "tenant"};
+//  4. Duplicates: fields sharing a key are collapsed into one, keeping the
+//     position of the first occurrence and either the last value ("last",
+//     the default) or the first ("first"); "keep" disables collapsing;
+//  5. Promote: fields moved into Pack attributes, e.g.
+//     {"trace_id": "trace_id"} sets Pack.TraceID from a trace_id field.
+//
+// Pack attribute names are canonical field names ("trace_id", "op",
+// "instance_id", ...) or registered custom attribute names, as in
+// context.Pack.Lookup. A promoted value that does not fit its custom
+// attribute's type is left in place; the record is still delivered and
+// the failure is returned with it as a partial stage error.
+//
+// The stage is copy-on-write: the caller's field slice is never modified.
+package rename
@@ -0,0 +1,331 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package rename
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "rename"
+
+// Duplicate handling modes.
+const (
+	DuplicatesLast  = "last"
+	DuplicatesFirst = "first"
+	DuplicatesKeep  = "keep"
+)
+
+// CaseSnake selects snake_case key normalization.
+const CaseSnake = "snake"
+
+// maxCached bounds the number of memoized key conversions.
+const maxCached = 4096
+
+var (
+	// ErrConfigInvalid is returned when a rename configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid rename config")
+)
+
+// Config is the configuration payload of the "rename" plugin.
+type Config struct {
+	// Keys maps old field keys to new ones.
+	Keys map[string]string `json:"keys,omitempty" yaml:"keys,omitempty"`
+
+	// Case is "" (keys are kept) or CaseSnake.
+	Case string `json:"case,omitempty" yaml:"case,omitempty"`
+
+	// Duplicates is DuplicatesLast (default), DuplicatesFirst or
+	// DuplicatesKeep.
+	Duplicates string `json:"duplicates,omitempty" yaml:"duplicates,omitempty"`
+
+	// Promote maps field keys to the Pack attributes they are moved into.
+	Promote map[string]string `json:"promote,omitempty" yaml:"promote,omitempty"`
+
+	// Demote maps Pack attributes to the field keys they are moved into.
+	Demote map[string]string `json:"demote,omitempty" yaml:"demote,omitempty"`
+}
+
+// Builder builds "rename" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "rename".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// demotion is one Demote entry.
+type demotion struct {
+	attr, key string
+}
+
+// Stage is the "rename" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name       string
+	enabled    bool
+	keys       map[string]string
+	snake      bool
+	duplicates string
+	promote    map[string]string
+	demote     []demotion
+
+	// cache memoizes key conversions (string -> string).
+	cache  sync.Map
+	cached atomic.Int64
+}
+
+var _ plugin.Enricher = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{
+		name:       name,
+		enabled:    enabled,
+		keys:       cfg.Keys,
+		duplicates: DuplicatesLast,
+		promote:    cfg.Promote,
+	}
+	for from, to := range cfg.Keys {
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("%w: keys: empty key in %q -> %q", ErrConfigInvalid, from, to)
+		}
+	}
+	switch cfg.Case {
+	case "":
+	case CaseSnake:
+		s.snake = true
+	default:
+		return nil, fmt.Errorf("%w: unknown case %q", ErrConfigInvalid, cfg.Case)
+	}
+	switch cfg.Duplicates {
+	case "":
+	case DuplicatesLast, DuplicatesFirst, DuplicatesKeep:
+		s.duplicates = cfg.Duplicates
+	default:
+		return nil, fmt.Errorf("%w: unknown duplicates mode %q", ErrConfigInvalid, cfg.Duplicates)
+	}
+	for key, attr := range cfg.Promote {
+		if key == "" {
+			return nil, fmt.Errorf("%w: promote: empty field key", ErrConfigInvalid)
+		}
+		if err := checkAttr(attr); err != nil {
+			return nil, fmt.Errorf("%w: promote %q: %w", ErrConfigInvalid, key, err)
+		}
+	}
+	for attr, key := range cfg.Demote {
+		if key == "" {
+			return nil, fmt.Errorf("%w: demote %q: empty field key", ErrConfigInvalid, attr)
+		}
+		if err := checkAttr(attr); err != nil {
+			return nil, fmt.Errorf("%w: demote: %w", ErrConfigInvalid, err)
+		}
+		s.demote = append(s.demote, demotion{attr: attr, key: key})
+	}
+	sort.Slice(s.demote, func(i, j int) bool { return s.demote[i].attr < s.demote[j].attr })
+	return s, nil
+}
+
+// Process renames, collapses, promotes and demotes the fields of r.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	var out []field.Field
+	own := func() {
+		if out == nil {
+			out = append(make([]field.Field, 0, len(r.Fields)+len(s.demote)), r.Fields...)
+		}
+	}
+
+	if len(s.keys) > 0 || s.snake {
+		for i, f := range r.Fields {
+			if k := s.rename(f.Key); k != f.Key {
+				own()
+				out[i].Key = k
+			}
+		}
+	}
+	for _, d := range s.demote {
+		if v, ok := r.Ctx.Lookup(d.attr); ok {
+			own()
+			out = append(out, field.New(d.key, v))
+			r.Ctx = r.Ctx.Delete(d.attr)
+		}
+	}
+	if out != nil {
+		r.Fields = out
+	}
+	if s.duplicates != DuplicatesKeep {
+		if c, ok := collapse(r.Fields, s.duplicates == DuplicatesLast); ok {
+			r.Fields = c
+		}
+	}
+
+	var errs []error
+	if len(s.promote) > 0 {
+		kept := r.Fields[:0:0]
+		moved := false
+		for _, f := range r.Fields {
+			attr, ok := s.promote[f.Key]
+			if !ok {
+				kept = append(kept, f)
+				continue
+			}
+			p, err := r.Ctx.Set(attr, f.Value)
+			if err != nil {
+				errs = append(errs, err)
+				kept = append(kept, f)
+				continue
+			}
+			r.Ctx = p
+			moved = true
+		}
+		if moved {
+			r.Fields = kept
+		}
+	}
+	return r, stage.Continue, errors.Join(errs...)
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// rename returns the new key for k.
+func (s *Stage) rename(k string) string {
+	if to, ok := s.keys[k]; ok {
+		return to
+	}
+	if !s.snake {
+		return k
+	}
+	if v, ok := s.cache.Load(k); ok {
+		return v.(string)
+	}
+	out := toSnake(k)
+	if to, ok := s.keys[out]; ok {
+		out = to
+	}
+	if s.cached.Load() < maxCached {
+		if _, loaded := s.cache.LoadOrStore(k, out); !loaded {
+			s.cached.Add(1)
+		}
+	}
+	return out
+}
+
+// collapse merges fields sharing a key, keeping the position of the first
+// occurrence and the last (or first) value. It reports false and returns
+// fs unchanged when there are no duplicates.
+func collapse(fs []field.Field, last bool) ([]field.Field, bool) {
+	if len(fs) < 2 {
+		return fs, false
+	}
+	idx := make(map[string]int, len(fs))
+	var out []field.Field
+	for i, f := range fs {
+		j, dup := idx[f.Key]
+		if !dup {
+			idx[f.Key] = len(idx)
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]field.Field, 0, len(fs)-1), fs[:i]...)
+		}
+		if last {
+			out[j].Value = f.Value
+		}
+	}
+	if out == nil {
+		return fs, false
+	}
+	return out, true
+}
+
+// toSnake converts camelCase, PascalCase and kebab-case keys to
+// snake_case. Acronyms are kept together ("HTTPStatus" -> "http_status").
+// Existing underscores, including leading ones such as "_truncated", are
+// kept as they are.
+func toSnake(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	b.Grow(len(s) + 4)
+	underscore := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteByte('_')
+		}
+	}
+	for i, r := range rs {
+		switch {
+		case r == '_':
+			b.WriteByte('_')
+		case r == '-' || r == ' ':
+			if !strings.HasSuffix(b.String(), "_") {
+				b.WriteByte('_')
+			}
+		case unicode.IsUpper(r):
+			if i > 0 {
+				prev := rs[i-1]
+				nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					underscore()
+				}
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// checkAttr reports whether name is a fixed Pack attribute or a
+// registered custom attribute.
+func checkAttr(name string) error {
+	if name == "" {
+		return errors.New("empty attribute name")
+	}
+	if _, err := (dlogctx.Pack{}).Set(name, nil); errors.Is(err, dlogctx.ErrAttrUnknown) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package rename
+
+import (
+	"context"
+	"testing"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/pipeline"
+)
+
+func TestToSnake(t *testing.T) {
+	tests := map[string]string{
+		"user_id":         "user_id",
+		"userId":          "user_id",
+		"userID":          "user_id",
+		"UserID":          "user_id",
+		"HTTPStatus":      "http_status",
+		"getHTTPResponse": "get_http_response",
+		"ipV4Addr":        "ip_v4_addr",
+		"page2Count":      "page2_count",
+		"request-id":      "request_id",
+		"request id":      "request_id",
+		"Request-ID":      "request_id",
+		"_truncated":      "_truncated",
+		"_pipeline_error": "_pipeline_error",
+		"_traceId":        "_trace_id",
+		"__meta":          "__meta",
+		"-flag":           "_flag",
+		"trailing_":       "trailing_",
+		"":                "",
+	}
+	for in, want := range tests {
+		if got := toSnake(in); got != want {
+			t.Errorf("toSnake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+var attemptKey = dlogctx.NewKey[int]("rename_test_attempt", nil)
+
+func TestPromoteTypeMismatchKeepsRecord(t *testing.T) {
+	s, err := New("r", Config{Promote: map[string]string{
+		"attempt":  attemptKey.Name(),
+		"trace_id": "trace_id",
+	}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []record.Record
+	c := pipeline.NewChain([]stage.Stage{s}, func(_ context.Context, r record.Record) error {
+		got = append(got, r)
+		return nil
+	})
+	r := record.Record{Message: "m", Fields: []field.Field{
+		field.New("attempt", "three"),
+		field.New("trace_id", "abc"),
+	}}
+	if err := c.Emit(context.Background(), r); err != nil {
+		t.Fatalf("Emit error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("delivered %d records, want 1", len(got))
+	}
+	out := got[0]
+	if len(out.Fields) != 1 || out.Fields[0].Key != "attempt" || out.Fields[0].Value != "three" {
+		t.Errorf("Fields = %v, want the attempt field left in place", out.Fields)
+	}
+	if out.Ctx.TraceID != "abc" {
+		t.Errorf("Ctx.TraceID = %q, want %q", out.Ctx.TraceID, "abc")
+	}
+	if _, ok := attemptKey.Get(out.Ctx); ok {
+		t.Error("attempt was promoted despite the type mismatch")
+	}
+	if st := c.Stats()[0]; st.Errors != 1 {
+		t.Errorf("Stats().Errors = %d, want 1", st.Errors)
+	}
+}
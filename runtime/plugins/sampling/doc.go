@@ -0,0 +1,37 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sampling implements the "sampling" plugin: probabilistic,
+// per-level sampling that is consistent across a distributed trace.
+//
+// Each level has a keep probability in [0, 1]; for example
+//
+//	{"rates": {"info": 0.1, "debug": 0.01, "trace": 0.01}}
+//
+// keeps every warn+ record, 10% of info and 1% of debug/trace.
+//
+// For records with a Pack.TraceID the decision is derived from the trace
+// ID itself: the rightmost 56 bits of a W3C trace ID are random, and a
+// record is kept when that value is at or above the rejection threshold
+// (1-p)·2^56, the same rule used by OpenTelemetry consistent probability
+// sampling. Every service applying the same rate therefore keeps either
+// all or none of a trace's records, and a trace kept at rate p is also
+// kept at any rate above p. Records without a trace ID are sampled
+// independently at random.
+//
+// Kept records with p < 1 receive a fields.SampleRate field holding 1/p,
+// so backends can extrapolate the original counts.
+package sampling
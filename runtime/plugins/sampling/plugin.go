@@ -0,0 +1,171 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sampling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "sampling"
+
+var (
+	// ErrConfigInvalid is returned when a sampling configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid sampling config")
+)
+
+// Config is the configuration payload of the "sampling" plugin.
+type Config struct {
+	// Rates maps level names (as accepted by level.ParseLevel) to keep
+	// probabilities in [0, 1].
+	Rates map[string]float64 `json:"rates" yaml:"rates"`
+
+	// Default is the keep probability of levels missing from Rates.
+	// If nil, 1 (keep everything) is used.
+	Default *float64 `json:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// randomBits is the number of random low-order bits of a W3C trace ID.
+const randomBits = 56
+
+// levelRate is the compiled sampling rule for one level.
+type levelRate struct {
+	rate float64
+	// threshold is the rejection threshold: keep iff randomness >= threshold.
+	threshold uint64
+	// field is the sample_rate field added to kept records; zero if rate is 1.
+	field field.Field
+}
+
+// Builder builds "sampling" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "sampling".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and compiles the rates.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "sampling" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name    string
+	enabled bool
+	rates   [level.Fatal + 1]levelRate
+}
+
+var _ plugin.Sampler = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	def := 1.0
+	if cfg.Default != nil {
+		def = *cfg.Default
+	}
+	if err := checkRate("default", def); err != nil {
+		return nil, err
+	}
+
+	s := &Stage{name: name, enabled: enabled}
+	for l := range s.rates {
+		s.rates[l] = compileRate(def)
+	}
+	for name, rate := range cfg.Rates {
+		lvl, err := level.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrConfigInvalid, err)
+		}
+		if err := checkRate(name, rate); err != nil {
+			return nil, err
+		}
+		s.rates[lvl] = compileRate(rate)
+	}
+	return s, nil
+}
+
+// Process keeps or drops r according to the rate of its level.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	if r.Level < 0 || int(r.Level) >= len(s.rates) {
+		return r, stage.Continue, nil
+	}
+	lr := &s.rates[r.Level]
+	switch {
+	case lr.rate >= 1:
+		return r, stage.Continue, nil
+	case lr.rate <= 0:
+		return r, stage.Drop, nil
+	}
+	if randomness(r.Ctx.TraceID) < lr.threshold {
+		return r, stage.Drop, nil
+	}
+	return r.WithFields(lr.field), stage.Continue, nil
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// checkRate validates a keep probability.
+func checkRate(name string, rate float64) error {
+	if !(rate >= 0 && rate <= 1) {
+		return fmt.Errorf("%w: rate %q must be within [0, 1], got %v", ErrConfigInvalid, name, rate)
+	}
+	return nil
+}
+
+// compileRate precomputes the threshold and sample_rate field of a rate.
+func compileRate(rate float64) levelRate {
+	lr := levelRate{rate: rate}
+	if rate > 0 && rate < 1 {
+		lr.threshold = uint64((1 - rate) * (1 << randomBits))
+		lr.field = field.New(fields.SampleRate, 1/rate)
+	}
+	return lr
+}
+
+// randomness returns the 56 random bits of a W3C trace ID (its last 14
+// hex characters), or a fresh random value when the trace ID is missing
+// or malformed.
+func randomness(traceID string) uint64 {
+	if len(traceID) == 32 {
+		if v, err := strconv.ParseUint(traceID[32-randomBits/4:], 16, 64); err == nil {
+			return v
+		}
+	}
+	return rand.Uint64() >> (64 - randomBits)
+}
@@ -0,0 +1,36 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package throttle implements the "throttle" plugin: per-message
+// throttling in the style of zap's sampler.
+//
+// Records are grouped by (level, message). Within each interval the first
+// First records of a group pass, after that only every Thereafter-th one;
+// the rest are dropped and counted. When a group's interval rolls over
+// and records were suppressed in it, the stage emits a summary record with
+// the same level and message, the identity part of the Pack
+// (context.Pack.Identity) and a fields.Suppressed field holding the count.
+//
+// Summaries are emitted through the stage.Emitter the pipeline binds to
+// the stage (see stage.Deferrer): lazily when the group is seen again, and
+// for idle groups by a sweep that runs at most once per interval on the
+// record path and on Flush. Idle groups are forgotten by the same sweep,
+// so memory is proportional to the number of distinct messages seen per
+// interval.
+//
+// State is split over lock shards selected by a hash of the key, so hot
+// loops logging different messages do not contend on one mutex.
+package throttle
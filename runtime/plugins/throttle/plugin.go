@@ -0,0 +1,259 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "throttle"
+
+// Defaults applied to zero Config values.
+const (
+	DefaultInterval   = time.Second
+	DefaultFirst      = 100
+	DefaultThereafter = 100
+)
+
+// shards is the number of lock shards; a power of two.
+const shards = 64
+
+var (
+	// ErrConfigInvalid is returned when a throttle configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid throttle config")
+)
+
+// Config is the configuration payload of the "throttle" plugin.
+type Config struct {
+	// Interval is the throttling window as a time.ParseDuration string;
+	// default DefaultInterval.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+
+	// First is the number of records per key let through in each
+	// interval; default DefaultFirst.
+	First int `json:"first,omitempty" yaml:"first,omitempty"`
+
+	// Thereafter lets every Thereafter-th record through once First is
+	// exhausted; 0 suppresses all of them. Default DefaultThereafter.
+	Thereafter *int `json:"thereafter,omitempty" yaml:"thereafter,omitempty"`
+}
+
+// Builder builds "throttle" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "throttle".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// key identifies a throttled group.
+type key struct {
+	level   level.Level
+	message string
+}
+
+// counter is the state of one group in the current interval.
+type counter struct {
+	start      time.Time
+	n          uint64
+	suppressed uint64
+	// pack is the identity of the first suppressed record.
+	pack dlogctx.Pack
+}
+
+// shard is a lock shard, padded to keep hot mutexes on separate cache lines.
+type shard struct {
+	mu sync.Mutex
+	m  map[key]*counter
+	_  [48]byte
+}
+
+// Stage is the "throttle" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name       string
+	enabled    bool
+	interval   time.Duration
+	first      uint64
+	thereafter uint64
+	now        func() time.Time
+
+	seed      maphash.Seed
+	shards    [shards]shard
+	nextSweep atomic.Int64
+	emit      atomic.Pointer[stage.Emitter]
+}
+
+var (
+	_ plugin.Throttler = (*Stage)(nil)
+	_ stage.Deferrer   = (*Stage)(nil)
+)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{
+		name:       name,
+		enabled:    enabled,
+		interval:   DefaultInterval,
+		first:      DefaultFirst,
+		thereafter: DefaultThereafter,
+		now:        time.Now,
+		seed:       maphash.MakeSeed(),
+	}
+	if cfg.Interval != "" {
+		d, err := time.ParseDuration(cfg.Interval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: interval %q must be a positive duration", ErrConfigInvalid, cfg.Interval)
+		}
+		s.interval = d
+	}
+	switch {
+	case cfg.First < 0:
+		return nil, fmt.Errorf("%w: first must not be negative", ErrConfigInvalid)
+	case cfg.First > 0:
+		s.first = uint64(cfg.First)
+	}
+	if cfg.Thereafter != nil {
+		if *cfg.Thereafter < 0 {
+			return nil, fmt.Errorf("%w: thereafter must not be negative", ErrConfigInvalid)
+		}
+		s.thereafter = uint64(*cfg.Thereafter)
+	}
+	for i := range s.shards {
+		s.shards[i].m = make(map[key]*counter)
+	}
+	return s, nil
+}
+
+// Bind sets the Emitter used for summary records.
+func (s *Stage) Bind(e stage.Emitter) { s.emit.Store(&e) }
+
+// Process lets r through or drops it according to its group's counters.
+func (s *Stage) Process(ctx context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	now := s.now()
+	var summaries []record.Record
+	if next := s.nextSweep.Load(); now.UnixNano() >= next && s.nextSweep.CompareAndSwap(next, now.Add(s.interval).UnixNano()) {
+		summaries = s.sweep(now, summaries)
+	}
+
+	k := key{level: r.Level, message: r.Message}
+	sh := &s.shards[(maphash.String(s.seed, k.message)^uint64(k.level))&(shards-1)]
+	sh.mu.Lock()
+	c := sh.m[k]
+	switch {
+	case c == nil:
+		c = &counter{start: now}
+		sh.m[k] = c
+	case now.Sub(c.start) >= s.interval:
+		if c.suppressed > 0 {
+			summaries = append(summaries, s.summary(k, c, now))
+		}
+		*c = counter{start: now}
+	}
+	c.n++
+	pass := c.n <= s.first || (s.thereafter > 0 && (c.n-s.first)%s.thereafter == 0)
+	if !pass {
+		if c.suppressed == 0 {
+			c.pack = r.Ctx.Identity()
+		}
+		c.suppressed++
+	}
+	sh.mu.Unlock()
+
+	if len(summaries) > 0 {
+		// Summaries concern other records: a failure to deliver them is
+		// the pipeline's to account for (see stage.Emitter), not r's.
+		_ = s.release(ctx, summaries)
+	}
+	if !pass {
+		return r, stage.Drop, nil
+	}
+	return r, stage.Continue, nil
+}
+
+// Flush emits the summaries of all groups whose interval has ended and
+// forgets them.
+func (s *Stage) Flush(ctx context.Context) error {
+	if summaries := s.sweep(s.now(), nil); len(summaries) > 0 {
+		return s.release(ctx, summaries)
+	}
+	return nil
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// sweep removes groups whose interval has ended, appending their
+// summaries to out.
+func (s *Stage) sweep(now time.Time, out []record.Record) []record.Record {
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		for k, c := range sh.m {
+			if now.Sub(c.start) < s.interval {
+				continue
+			}
+			if c.suppressed > 0 {
+				out = append(out, s.summary(k, c, now))
+			}
+			delete(sh.m, k)
+		}
+		sh.mu.Unlock()
+	}
+	return out
+}
+
+// summary builds the summary record of a group.
+func (s *Stage) summary(k key, c *counter, now time.Time) record.Record {
+	return record.NewRecord(now, k.level, k.message, c.pack,
+		[]field.Field{field.New(fields.Suppressed, c.suppressed)}, nil)
+}
+
+// release emits summaries if the stage is bound; otherwise they are lost.
+func (s *Stage) release(ctx context.Context, rs []record.Record) error {
+	if e := s.emit.Load(); e != nil {
+		return (*e).Emit(ctx, rs...)
+	}
+	return nil
+}
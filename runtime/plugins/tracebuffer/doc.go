@@ -0,0 +1,40 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package tracebuffer implements the "trace_buffer" plugin: tail-based
+// buffering that emits detailed logs only for traces that failed.
+//
+// Records at or below the Hold level (debug by default) that carry a
+// Pack.TraceID are not passed on. They are kept in a per-trace buffer
+// instead. When a record at or above the Release level (error by default)
+// arrives for the same trace, the held records are released in their
+// original order ahead of it. Later held-level records of that trace then
+// pass straight through. A failure to deliver the released records is
+// counted in Stats.ReleaseErrors and does not affect the triggering
+// record. Traces that never fail are forgotten with their
+// records once their TTL expires.
+//
+// Memory is bounded three ways: by TTL (measured from the first held
+// record of a trace), by the number of buffered traces and by the
+// estimated size of all held records. When a bound is exceeded the oldest
+// traces are evicted and their records dropped.
+//
+// Holding records relies on the stage.Defer decision: the stage must run
+// in a pipeline that binds stage.Deferrer stages (such as
+// runtime/pipeline.Chain). Until it is bound, the stage lets every record
+// through. Held records are retained as-is, so producers must not reuse
+// the Fields slice of an emitted record.
+package tracebuffer
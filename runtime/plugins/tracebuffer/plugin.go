@@ -0,0 +1,317 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tracebuffer
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "trace_buffer"
+
+// Defaults applied to zero Config values.
+const (
+	DefaultTTL       = 30 * time.Second
+	DefaultMaxTraces = 10000
+	DefaultMaxBytes  = 16 << 20
+)
+
+var (
+	// ErrConfigInvalid is returned when a trace_buffer configuration is rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid trace_buffer config")
+)
+
+// Config is the configuration payload of the "trace_buffer" plugin.
+type Config struct {
+	// Hold is the highest level that is buffered; default debug.
+	Hold *level.Level `json:"hold,omitempty" yaml:"hold,omitempty"`
+
+	// Release is the lowest level that releases a trace's buffer;
+	// default error. It must be above Hold.
+	Release *level.Level `json:"release,omitempty" yaml:"release,omitempty"`
+
+	// TTL is how long a trace is buffered after its first held record,
+	// as a time.ParseDuration string; default DefaultTTL.
+	TTL string `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+
+	// MaxTraces bounds the number of buffered traces; default DefaultMaxTraces.
+	MaxTraces int `json:"max_traces,omitempty" yaml:"max_traces,omitempty"`
+
+	// MaxBytes bounds the estimated size of all held records; default
+	// DefaultMaxBytes.
+	MaxBytes int `json:"max_bytes,omitempty" yaml:"max_bytes,omitempty"`
+}
+
+// Stats is a snapshot of the buffer state and counters.
+type Stats struct {
+	// Traces and Bytes describe what is currently buffered.
+	Traces int
+	Bytes  int
+
+	// Released counts records emitted because their trace failed.
+	Released uint64
+	// ReleaseErrors counts releases whose records the rest of the
+	// pipeline failed to process. The triggering record is not affected.
+	ReleaseErrors uint64
+	// Expired counts records dropped because their trace expired.
+	Expired uint64
+	// Evicted counts records dropped to honour MaxTraces or MaxBytes.
+	Evicted uint64
+}
+
+// Builder builds "trace_buffer" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "trace_buffer".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and validates it.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// entry is the buffer of one trace.
+type entry struct {
+	id       string
+	expires  time.Time
+	records  []record.Record
+	size     int
+	released bool
+	elem     *list.Element
+}
+
+// Stage is the "trace_buffer" pipeline stage. It is safe for concurrent use.
+type Stage struct {
+	name      string
+	enabled   bool
+	hold      level.Level
+	release   level.Level
+	ttl       time.Duration
+	maxTraces int
+	maxBytes  int
+	now       func() time.Time
+
+	emit atomic.Pointer[stage.Emitter]
+
+	mu     sync.Mutex
+	traces map[string]*entry
+	order  *list.List // of *entry, oldest first
+	bytes  int
+
+	released, expired, evicted atomic.Uint64
+	releaseErrors              atomic.Uint64
+}
+
+var (
+	_ plugin.Sampler = (*Stage)(nil)
+	_ stage.Deferrer = (*Stage)(nil)
+)
+
+// New returns a Stage with the given name, configuration and state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	s := &Stage{
+		name:      name,
+		enabled:   enabled,
+		hold:      level.Debug,
+		release:   level.Error,
+		ttl:       DefaultTTL,
+		maxTraces: DefaultMaxTraces,
+		maxBytes:  DefaultMaxBytes,
+		now:       time.Now,
+		traces:    make(map[string]*entry),
+		order:     list.New(),
+	}
+	if cfg.Hold != nil {
+		s.hold = *cfg.Hold
+	}
+	if cfg.Release != nil {
+		s.release = *cfg.Release
+	}
+	if s.hold >= s.release {
+		return nil, fmt.Errorf("%w: hold level %s must be below release level %s", ErrConfigInvalid, s.hold, s.release)
+	}
+	if cfg.TTL != "" {
+		d, err := time.ParseDuration(cfg.TTL)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: ttl %q must be a positive duration", ErrConfigInvalid, cfg.TTL)
+		}
+		s.ttl = d
+	}
+	switch {
+	case cfg.MaxTraces < 0:
+		return nil, fmt.Errorf("%w: max_traces must not be negative", ErrConfigInvalid)
+	case cfg.MaxTraces > 0:
+		s.maxTraces = cfg.MaxTraces
+	}
+	switch {
+	case cfg.MaxBytes < 0:
+		return nil, fmt.Errorf("%w: max_bytes must not be negative", ErrConfigInvalid)
+	case cfg.MaxBytes > 0:
+		s.maxBytes = cfg.MaxBytes
+	}
+	return s, nil
+}
+
+// Bind sets the Emitter used to release held records.
+func (s *Stage) Bind(e stage.Emitter) { s.emit.Store(&e) }
+
+// Process holds, releases or passes r depending on its level and trace.
+func (s *Stage) Process(ctx context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	id := r.Ctx.TraceID
+	if id == "" || (r.Level > s.hold && r.Level < s.release) {
+		return r, stage.Continue, nil
+	}
+	emit := s.emit.Load()
+	if emit == nil {
+		return r, stage.Continue, nil
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	s.expireLocked(now)
+	e := s.traces[id]
+
+	if r.Level >= s.release {
+		if e == nil {
+			e = s.addLocked(id, now)
+		}
+		held := e.records
+		s.bytes -= e.size
+		e.records, e.size, e.released = nil, 0, true
+		s.mu.Unlock()
+
+		if len(held) == 0 {
+			return r, stage.Continue, nil
+		}
+		s.released.Add(uint64(len(held)))
+		// The trigger is the record this stage exists to keep: a failure
+		// to deliver the held records must not cost it its own delivery.
+		if err := (*emit).Emit(ctx, held...); err != nil {
+			s.releaseErrors.Add(1)
+		}
+		return r, stage.Continue, nil
+	}
+
+	if e != nil && e.released {
+		s.mu.Unlock()
+		return r, stage.Continue, nil
+	}
+	if e == nil {
+		e = s.addLocked(id, now)
+	}
+	n := size(r)
+	e.records = append(e.records, r)
+	e.size += n
+	s.bytes += n
+	for s.bytes > s.maxBytes && s.order.Len() > 0 {
+		s.evicted.Add(uint64(s.removeLocked(s.order.Front().Value.(*entry))))
+	}
+	s.mu.Unlock()
+	return r, stage.Defer, nil
+}
+
+// Flush drops the records of expired traces. Held records of live traces
+// stay buffered: they are emitted only if their trace fails.
+func (s *Stage) Flush(context.Context) error {
+	s.mu.Lock()
+	s.expireLocked(s.now())
+	s.mu.Unlock()
+	return nil
+}
+
+// Stats returns a snapshot of the buffer state and counters.
+func (s *Stage) Stats() Stats {
+	s.mu.Lock()
+	st := Stats{Traces: len(s.traces), Bytes: s.bytes}
+	s.mu.Unlock()
+	st.Released = s.released.Load()
+	st.ReleaseErrors = s.releaseErrors.Load()
+	st.Expired = s.expired.Load()
+	st.Evicted = s.evicted.Load()
+	return st
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled }
+
+// addLocked creates the entry of trace id, evicting the oldest traces if
+// MaxTraces would be exceeded.
+func (s *Stage) addLocked(id string, now time.Time) *entry {
+	for s.order.Len() >= s.maxTraces {
+		s.evicted.Add(uint64(s.removeLocked(s.order.Front().Value.(*entry))))
+	}
+	e := &entry{id: id, expires: now.Add(s.ttl)}
+	e.elem = s.order.PushBack(e)
+	s.traces[id] = e
+	return e
+}
+
+// expireLocked removes the traces whose TTL has passed. Entries are
+// ordered by creation and share one TTL, so only the front is inspected.
+func (s *Stage) expireLocked(now time.Time) {
+	for f := s.order.Front(); f != nil; f = s.order.Front() {
+		e := f.Value.(*entry)
+		if now.Before(e.expires) {
+			return
+		}
+		s.expired.Add(uint64(s.removeLocked(e)))
+	}
+}
+
+// removeLocked forgets e and returns the number of records it held.
+func (s *Stage) removeLocked(e *entry) int {
+	s.order.Remove(e.elem)
+	delete(s.traces, e.id)
+	s.bytes -= e.size
+	return len(e.records)
+}
+
+// recordOverhead approximates the fixed cost of a held record.
+const recordOverhead = 256
+
+// size estimates the memory retained by a held record.
+func size(r record.Record) int {
+	n := recordOverhead + len(r.Message)
+	for _, f := range r.Fields {
+		n += len(f.Key) + 32
+		if v, ok := f.Value.(string); ok {
+			n += len(v)
+		}
+	}
+	return n
+}
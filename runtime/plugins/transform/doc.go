@@ -0,0 +1,63 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package transform implements the "transform" plugin, which reshapes
+// records with an ordered list of operations:
+//
+//	{"ops": [
+//	  {"op": "rename", "from": "userId", "key": "user_id"},
+//	  {"op": "copy", "from": "tenant_id", "key": "tenant"},
+//	  {"op": "hash", "key": "email", "algorithm": "sha256"},
+//	  {"op": "parse_json", "key": "payload"},
+//	  {"op": "level", "level": "warn", "when": "fields.status >= 500"}
+//	]}
+//
+// Operations:
+//
+//   - set: sets field key to value;
+//   - delete: removes every field named key;
+//   - rename: renames every field named from to key;
+//   - copy: copies the Pack attribute from (a canonical field name, the
+//     JSON name of a fixed attribute or a registered custom attribute
+//     name, see context.ResolveAttr) into field key, which defaults to
+//     the canonical name of from;
+//   - lower, upper: case-maps a string field;
+//   - hash: replaces a field with the hex digest of its text, using
+//     algorithm sha256 (default), sha1, md5 or fnv64a;
+//   - substring: keeps length runes (all if unset) of a string field,
+//     starting at rune start;
+//   - parse_json: replaces a string field with the JSON value it holds;
+//   - level: sets the record level.
+//
+// The computing operations (lower, upper, hash, substring, parse_json)
+// read field from, which defaults to key, and write field key. Setting a
+// field replaces its last occurrence or appends it. Operations whose
+// source is missing are skipped; a source of the wrong type, or text that
+// is not valid JSON, is left in place and the remaining operations still
+// run. Such failures are returned together with the transformed record
+// as a partial error: the record is always delivered, the error is
+// counted and, under on_error "annotate", attached to the record.
+//
+// Every operation may carry a "when" expression (see runtime/expr); it is
+// evaluated against the record as transformed by the previous operations.
+//
+// The whole list is compiled by Build and by Update, so invalid operations
+// are rejected before they can affect records. That includes unknown
+// context attributes and parameters the operation does not use, such as
+// "value" on hash. Stage.Watch follows a provider stream and swaps the
+// list atomically. The stage is copy-on-write: the caller's field slice is
+// never modified.
+package transform
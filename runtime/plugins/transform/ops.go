@@ -0,0 +1,296 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package transform
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/runtime/expr"
+)
+
+// op is a compiled operation.
+type op struct {
+	when  *expr.Program
+	apply func(t *target) error
+}
+
+// compile validates ops and compiles them in order.
+func compile(ops []Op) ([]op, error) {
+	out := make([]op, 0, len(ops))
+	for i, o := range ops {
+		c, err := compileOp(o)
+		if err != nil {
+			return nil, fmt.Errorf("%w: ops[%d] (%s): %w", ErrConfigInvalid, i, o.Op, err)
+		}
+		if o.When != "" {
+			if c.when, err = expr.Compile(o.When); err != nil {
+				return nil, fmt.Errorf("%w: ops[%d] (%s): when: %w", ErrConfigInvalid, i, o.Op, err)
+			}
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// params lists the parameters each operation uses, besides When.
+var params = map[string][]string{
+	OpSet:       {"key", "value"},
+	OpDelete:    {"key"},
+	OpRename:    {"key", "from"},
+	OpCopy:      {"key", "from"},
+	OpLower:     {"key", "from"},
+	OpUpper:     {"key", "from"},
+	OpHash:      {"key", "from", "algorithm"},
+	OpSubstring: {"key", "from", "start", "length"},
+	OpParseJSON: {"key", "from"},
+	OpLevel:     {"level"},
+}
+
+// checkParams rejects parameters that o sets but its operation ignores,
+// which are likely mistakes.
+func checkParams(o Op) error {
+	set := map[string]bool{
+		"key":       o.Key != "",
+		"from":      o.From != "",
+		"value":     o.Value != nil,
+		"algorithm": o.Algorithm != "",
+		"start":     o.Start != 0,
+		"length":    o.Length != nil,
+		"level":     o.Level != "",
+	}
+	for _, p := range params[o.Op] {
+		delete(set, p)
+	}
+	var unused []string
+	for p, ok := range set {
+		if ok {
+			unused = append(unused, p)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return fmt.Errorf("parameters %q are not used by this operation", unused)
+	}
+	return nil
+}
+
+func compileOp(o Op) (op, error) {
+	if _, ok := params[o.Op]; !ok {
+		return op{}, fmt.Errorf("unknown operation %q", o.Op)
+	}
+	if err := checkParams(o); err != nil {
+		return op{}, err
+	}
+	if o.Op == OpLevel {
+		if o.Level == "" {
+			return op{}, fmt.Errorf("level is required")
+		}
+		lvl, err := level.ParseLevel(o.Level)
+		if err != nil {
+			return op{}, err
+		}
+		return op{apply: func(t *target) error {
+			t.r.Level = lvl
+			return nil
+		}}, nil
+	}
+
+	key, from := o.Key, o.From
+	switch o.Op {
+	case OpRename, OpCopy:
+		if from == "" {
+			return op{}, fmt.Errorf("from is required")
+		}
+		if o.Op == OpCopy {
+			canon, ok := dlogctx.ResolveAttr(from)
+			if !ok {
+				return op{}, fmt.Errorf("unknown context attribute %q", from)
+			}
+			from = canon
+			if key == "" {
+				key = from
+			}
+		}
+	default:
+		if from == "" {
+			from = key
+		}
+	}
+	if key == "" {
+		return op{}, fmt.Errorf("key is required")
+	}
+
+	switch o.Op {
+	case OpSet:
+		v := o.Value
+		return op{apply: func(t *target) error {
+			t.set(key, v)
+			return nil
+		}}, nil
+
+	case OpDelete:
+		return op{apply: func(t *target) error {
+			if _, ok := t.get(key); !ok {
+				return nil
+			}
+			fs := t.fields()
+			kept := fs[:0]
+			for _, f := range fs {
+				if f.Key != key {
+					kept = append(kept, f)
+				}
+			}
+			t.r.Fields = kept
+			return nil
+		}}, nil
+
+	case OpRename:
+		return op{apply: func(t *target) error {
+			if _, ok := t.get(from); !ok {
+				return nil
+			}
+			fs := t.fields()
+			for i := range fs {
+				if fs[i].Key == from {
+					fs[i].Key = key
+				}
+			}
+			return nil
+		}}, nil
+
+	case OpCopy:
+		return op{apply: func(t *target) error {
+			if v, ok := t.r.Ctx.Lookup(from); ok {
+				t.set(key, v)
+			}
+			return nil
+		}}, nil
+
+	case OpLower:
+		return mapString(key, from, func(s string) (any, error) { return strings.ToLower(s), nil }), nil
+
+	case OpUpper:
+		return mapString(key, from, func(s string) (any, error) { return strings.ToUpper(s), nil }), nil
+
+	case OpHash:
+		newHash, err := hasher(o.Algorithm)
+		if err != nil {
+			return op{}, err
+		}
+		return op{apply: func(t *target) error {
+			v, ok := t.get(from)
+			if !ok {
+				return nil
+			}
+			h := newHash()
+			if s, ok := v.(string); ok {
+				h.Write([]byte(s))
+			} else {
+				fmt.Fprint(h, v)
+			}
+			t.set(key, hex.EncodeToString(h.Sum(nil)))
+			return nil
+		}}, nil
+
+	case OpSubstring:
+		if o.Start < 0 || (o.Length != nil && *o.Length < 0) {
+			return op{}, fmt.Errorf("start and length must not be negative")
+		}
+		start, length := o.Start, -1
+		if o.Length != nil {
+			length = *o.Length
+		}
+		return mapString(key, from, func(s string) (any, error) {
+			return substring(s, start, length), nil
+		}), nil
+
+	default: // OpParseJSON
+		return mapString(key, from, func(s string) (any, error) {
+			var v any
+			if err := json.Unmarshal([]byte(s), &v); err != nil {
+				return nil, err
+			}
+			return v, nil
+		}), nil
+	}
+}
+
+// mapString compiles an operation writing fn of string field from into key.
+func mapString(key, from string, fn func(string) (any, error)) op {
+	return op{apply: func(t *target) error {
+		v, ok := t.get(from)
+		if !ok {
+			return nil
+		}
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("dlog: transform: field %q is %T, not a string", from, v)
+		}
+		out, err := fn(s)
+		if err != nil {
+			return fmt.Errorf("dlog: transform: field %q: %w", from, err)
+		}
+		t.set(key, out)
+		return nil
+	}}
+}
+
+// hasher returns the constructor of the named digest.
+func hasher(name string) (func() hash.Hash, error) {
+	switch name {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "md5":
+		return md5.New, nil
+	case "fnv64a":
+		return func() hash.Hash { return fnv.New64a() }, nil
+	}
+	return nil, fmt.Errorf("unknown hash algorithm %q", name)
+}
+
+// substring returns length runes of s starting at rune start; a negative
+// length selects the rest of s.
+func substring(s string, start, length int) string {
+	i := 0
+	for n := 0; n < start && i < len(s); n++ {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	if length < 0 {
+		return s[i:]
+	}
+	j := i
+	for n := 0; n < length && j < len(s); n++ {
+		_, size := utf8.DecodeRuneInString(s[j:])
+		j += size
+	}
+	return s[i:j]
+}
@@ -0,0 +1,221 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/pipeline/plugin"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/provider"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/internal/spec"
+)
+
+// Kind is the plugin kind handled by Builder.
+const Kind = "transform"
+
+// Operation names.
+const (
+	OpSet       = "set"
+	OpDelete    = "delete"
+	OpRename    = "rename"
+	OpCopy      = "copy"
+	OpLower     = "lower"
+	OpUpper     = "upper"
+	OpHash      = "hash"
+	OpSubstring = "substring"
+	OpParseJSON = "parse_json"
+	OpLevel     = "level"
+)
+
+var (
+	// ErrConfigInvalid is returned when a transform configuration is
+	// rejected.
+	ErrConfigInvalid = errors.New("dlog: invalid transform config")
+)
+
+// Config is the configuration payload of the "transform" plugin.
+type Config struct {
+	// Ops are applied in order to every record.
+	Ops []Op `json:"ops" yaml:"ops"`
+}
+
+// Op is one transform operation; see the package documentation for the
+// parameters each operation uses.
+type Op struct {
+	// Op is the operation name, e.g. "set" or "hash".
+	Op string `json:"op" yaml:"op"`
+
+	// Key is the field written (or removed) by the operation.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+
+	// From is the source field, or the Pack attribute for "copy".
+	From string `json:"from,omitempty" yaml:"from,omitempty"`
+
+	// Value is the value stored by "set".
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+
+	// Algorithm is the digest used by "hash".
+	Algorithm string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+
+	// Start and Length select the runes kept by "substring".
+	Start  int  `json:"start,omitempty" yaml:"start,omitempty"`
+	Length *int `json:"length,omitempty" yaml:"length,omitempty"`
+
+	// Level is the level name set by "level".
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+
+	// When restricts the operation to records matching the expression.
+	When string `json:"when,omitempty" yaml:"when,omitempty"`
+}
+
+// Builder builds "transform" stages.
+type Builder struct{}
+
+var _ plugin.Builder = Builder{}
+
+// Kind returns "transform".
+func (Builder) Kind() string { return Kind }
+
+// Build decodes the Config payload and compiles its operations.
+func (Builder) Build(_ context.Context, s plugin.Specification) (stage.Stage, error) {
+	var cfg Config
+	if err := spec.Decode(s.Config, &cfg); err != nil {
+		return nil, err
+	}
+	return New(spec.Name(s), cfg, spec.Enabled(s))
+}
+
+// Stage is the "transform" pipeline stage.
+// It is safe for concurrent use; operations can be replaced at any time.
+type Stage struct {
+	name    string
+	enabled atomic.Bool
+	ops     atomic.Pointer[[]op]
+}
+
+var _ plugin.Enricher = (*Stage)(nil)
+
+// New returns a Stage with the given name, configuration and initial state.
+func New(name string, cfg Config, enabled bool) (*Stage, error) {
+	ops, err := compile(cfg.Ops)
+	if err != nil {
+		return nil, err
+	}
+	s := &Stage{name: name}
+	s.ops.Store(&ops)
+	s.enabled.Store(enabled)
+	return s, nil
+}
+
+// Process applies the operations to r. Failed operations do not stop
+// the others; their errors are returned with the transformed record.
+func (s *Stage) Process(_ context.Context, r record.Record) (record.Record, stage.Decision, error) {
+	t := target{r: r}
+	var errs []error
+	ops := *s.ops.Load()
+	for i := range ops {
+		o := &ops[i]
+		if o.when != nil && !o.when.Match(t.r) {
+			continue
+		}
+		if err := o.apply(&t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return t.r, stage.Continue, errors.Join(errs...)
+}
+
+// Name returns the stage name.
+func (s *Stage) Name() string { return s.name }
+
+// Enabled reports whether the stage is enabled.
+func (s *Stage) Enabled() bool { return s.enabled.Load() }
+
+// Update compiles cfg and atomically replaces the current operations.
+// On error the current operations are kept.
+func (s *Stage) Update(cfg Config) error {
+	ops, err := compile(cfg.Ops)
+	if err != nil {
+		return err
+	}
+	s.ops.Store(&ops)
+	return nil
+}
+
+// Watch follows stream and applies every published version of this
+// plugin's specification (matched by Kind and Name), including its
+// Enabled flag. Invalid configurations are reported to onError, which may
+// be nil, and leave the current operations in place.
+//
+// Watch blocks until ctx is done or the stream is closed.
+func (s *Stage) Watch(ctx context.Context, stream provider.Stream, onError func(error)) error {
+	return spec.Watch(ctx, stream, Kind, s.name, func(ps plugin.Specification) error {
+		var cfg Config
+		if err := spec.Decode(ps.Config, &cfg); err != nil {
+			return err
+		}
+		if err := s.Update(cfg); err != nil {
+			return err
+		}
+		s.enabled.Store(spec.Enabled(ps))
+		return nil
+	}, onError)
+}
+
+// target is the record being transformed. Its field slice is copied on
+// the first modification.
+type target struct {
+	r     record.Record
+	owned bool
+}
+
+// fields returns the record's fields for modification.
+func (t *target) fields() []field.Field {
+	if !t.owned {
+		t.r.Fields = append([]field.Field(nil), t.r.Fields...)
+		t.owned = true
+	}
+	return t.r.Fields
+}
+
+// get returns the value of the last field named key.
+func (t *target) get(key string) (any, bool) {
+	fs := t.r.Fields
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key == key {
+			return fs[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// set replaces the last field named key, or appends one.
+func (t *target) set(key string, v any) {
+	fs := t.fields()
+	for i := len(fs) - 1; i >= 0; i-- {
+		if fs[i].Key == key {
+			fs[i].Value = v
+			return
+		}
+	}
+	t.r.Fields = append(fs, field.New(key, v))
+}
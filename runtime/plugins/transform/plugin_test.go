@@ -0,0 +1,72 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/pipeline/stage"
+	"dirpx.dev/dlog/apis/record"
+	"dirpx.dev/dlog/runtime/pipeline"
+)
+
+func TestBadSourceKeepsRecord(t *testing.T) {
+	s, err := New("t", Config{Ops: []Op{
+		{Op: "lower", Key: "status"},
+		{Op: "parse_json", Key: "payload"},
+		{Op: "upper", Key: "method"},
+	}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []record.Record
+	c := pipeline.NewChain([]stage.Stage{s}, func(_ context.Context, r record.Record) error {
+		got = append(got, r)
+		return nil
+	})
+	r := record.Record{Message: "m", Fields: []field.Field{
+		field.New("status", 500),
+		field.New("payload", "{not json"),
+		field.New("method", "get"),
+	}}
+	if err := c.Emit(context.Background(), r); err != nil {
+		t.Fatalf("Emit error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("delivered %d records, want 1", len(got))
+	}
+	want := map[string]any{"status": 500, "payload": "{not json", "method": "GET"}
+	for k, v := range want {
+		if g, _ := lookup(got[0], k); g != v {
+			t.Errorf("%s = %v, want %v", k, g, v)
+		}
+	}
+	if st := c.Stats()[0]; st.Errors != 1 {
+		t.Errorf("Stats().Errors = %d, want 1", st.Errors)
+	}
+}
+
+func lookup(r record.Record, key string) (any, bool) {
+	for _, f := range r.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
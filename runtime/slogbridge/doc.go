@@ -0,0 +1,40 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package slogbridge connects the standard library log/slog package with
+// dlog.
+//
+// Handler is a slog.Handler that converts every slog.Record into a dlog
+// record.Record and pushes it through a pipeline.Pipeline, so libraries
+// that log via slog get the same redaction, sampling and sink fan-out as
+// code that uses the dlog API directly.
+//
+// Logger goes the other way: it implements apis.Logger, apis.FieldLogger
+// and apis.ContextLogger on top of any slog.Handler, so teams can adopt
+// the dlog API while keeping their existing slog handlers. Context Pack
+// attributes become slog attributes under the canonical field names.
+//
+// Source locations follow slog conventions: Logger stores the call site
+// in the slog record PC when LoggerOptions.AddCaller is set, and Handler
+// resolves it into record.Caller when HandlerOptions.AddSource is set.
+// Stacks captured by Logger (LoggerOptions.StackLevel) become
+// record.Stack. Both are free when disabled.
+//
+// Level mapping between slog and dlog is defined by FromSlogLevel and
+// ToSlogLevel. slog levels that fall between the well-known values
+// (for example slog.LevelInfo+2) are mapped to the closest dlog level
+// at or below them.
+package slogbridge
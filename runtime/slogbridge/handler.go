@@ -0,0 +1,264 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/pipeline"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// GroupStyle controls how slog groups are represented in dlog fields.
+type GroupStyle uint8
+
+const (
+	// GroupNested renders a group as a single field whose value is a
+	// map[string]any holding the group members (recursively).
+	GroupNested GroupStyle = iota
+
+	// GroupDotted flattens groups into top-level fields whose keys are
+	// joined with a dot, e.g. group "http" and key "status" become
+	// "http.status".
+	GroupDotted
+)
+
+// HandlerOptions configures a Handler.
+type HandlerOptions struct {
+	// Level reports the minimum slog level that is handled.
+	// If nil, slog.LevelInfo is used. Use a *slog.LevelVar for dynamic control.
+	Level slog.Leveler
+
+	// Extractor builds the context Pack from the ctx passed to Handle.
+	// If nil, context.FromContext is used.
+	Extractor dlogctx.Extractor
+
+	// Groups selects how WithGroup/slog.Group are represented.
+	// The zero value is GroupNested.
+	Groups GroupStyle
+
+	// AddSource resolves the PC of slog records into record.Caller.
+	// It is off by default because symbolization has a per-record cost.
+	AddSource bool
+}
+
+// Handler is a slog.Handler that forwards records into a dlog pipeline.
+//
+// Handler is immutable: WithAttrs and WithGroup return new handlers that
+// share the pipeline and options with the original. It is safe for
+// concurrent use as long as the underlying pipeline is.
+type Handler struct {
+	pipeline pipeline.Pipeline
+	opts     HandlerOptions
+
+	// attrs are the attributes added before any group was opened.
+	attrs []slog.Attr
+	// groups are the currently open groups, outermost first.
+	groups []openGroup
+}
+
+// openGroup is a group opened via WithGroup together with the attributes
+// added while it was the innermost group.
+type openGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a Handler that emits into p.
+// A nil opts is equivalent to the zero HandlerOptions.
+func NewHandler(p pipeline.Pipeline, opts *HandlerOptions) *Handler {
+	h := &Handler{pipeline: p}
+	if opts != nil {
+		h.opts = *opts
+	}
+	return h
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return l >= minLevel
+}
+
+// Handle converts r into a record.Record and emits it into the pipeline.
+//
+// A zero r.Time is preserved as a zero record time, as required by the
+// slog.Handler contract; downstream encoders are expected to omit it.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ex := h.opts.Extractor
+	if ex == nil {
+		ex = dlogctx.FromContext()
+	}
+	pack := ex.Extract(ctx)
+
+	// Collect record attributes; they belong to the innermost open group.
+	// A stack captured by Logger travels as a fields.Stack attribute and
+	// is moved into record.Stack.
+	var stack []record.Frame
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == fields.Stack && a.Value.Kind() == slog.KindAny {
+			if st, ok := a.Value.Any().([]record.Frame); ok {
+				stack = st
+				return true
+			}
+		}
+		attrs = append(attrs, a)
+		return true
+	})
+
+	// Wrap the record attributes into the open groups, innermost first.
+	// A group that ends up with no members is dropped entirely.
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		members := make([]slog.Attr, 0, len(g.attrs)+len(attrs))
+		members = append(members, g.attrs...)
+		members = append(members, attrs...)
+		attrs = []slog.Attr{{Key: g.name, Value: slog.GroupValue(members...)}}
+	}
+
+	// Fields bound to ctx via context.WithFields come first, as they are
+	// the outermost scope; handler and record attributes follow.
+	bound := dlogctx.FieldsFrom(ctx)
+	fs := make([]field.Field, 0, len(bound)+len(h.attrs)+len(attrs))
+	fs = append(fs, bound...)
+	fs = h.appendAttrs(fs, "", h.attrs)
+	fs = h.appendAttrs(fs, "", attrs)
+
+	rec := record.NewRecord(r.Time, FromSlogLevel(r.Level), r.Message, pack, fs, nil)
+	rec.Stack = stack
+	if h.opts.AddSource {
+		rec.Caller = record.FrameOf(r.PC)
+	}
+	return h.pipeline.Emit(ctx, rec)
+}
+
+// WithAttrs returns a handler whose records always include attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	out := h.clone()
+	if n := len(out.groups); n > 0 {
+		g := &out.groups[n-1]
+		g.attrs = append(append([]slog.Attr(nil), g.attrs...), attrs...)
+	} else {
+		out.attrs = append(append([]slog.Attr(nil), h.attrs...), attrs...)
+	}
+	return out
+}
+
+// WithGroup returns a handler that nests all subsequent attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	out := h.clone()
+	out.groups = append(out.groups, openGroup{name: name})
+	return out
+}
+
+// clone returns a copy of h with an independent groups slice.
+// Attribute slices are shared and must be copied before appending.
+func (h *Handler) clone() *Handler {
+	out := *h
+	out.groups = append([]openGroup(nil), h.groups...)
+	return &out
+}
+
+// appendAttrs converts attrs into fields and appends them to dst.
+// prefix is the dotted group path used in GroupDotted mode.
+func (h *Handler) appendAttrs(dst []field.Field, prefix string, attrs []slog.Attr) []field.Field {
+	for _, a := range attrs {
+		dst = h.appendAttr(dst, prefix, a)
+	}
+	return dst
+}
+
+// appendAttr converts a single attribute following the slog.Handler rules:
+// empty attributes are ignored, empty groups are ignored and groups with
+// an empty key are inlined into the parent.
+func (h *Handler) appendAttr(dst []field.Field, prefix string, a slog.Attr) []field.Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+
+	if a.Value.Kind() != slog.KindGroup {
+		return append(dst, field.New(prefix+a.Key, a.Value.Any()))
+	}
+
+	members := a.Value.Group()
+	if len(members) == 0 {
+		return dst
+	}
+	if a.Key == "" {
+		return h.appendAttrs(dst, prefix, members)
+	}
+	if h.opts.Groups == GroupDotted {
+		return h.appendAttrs(dst, prefix+a.Key+".", members)
+	}
+
+	m := groupMap(members)
+	if len(m) == 0 {
+		return dst
+	}
+	return append(dst, field.New(prefix+a.Key, m))
+}
+
+// groupMap converts group members into a nested map, applying the same
+// rules as appendAttr.
+func groupMap(attrs []slog.Attr) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() != slog.KindGroup {
+			m[a.Key] = a.Value.Any()
+			continue
+		}
+		members := a.Value.Group()
+		if len(members) == 0 {
+			continue
+		}
+		if a.Key == "" {
+			for k, v := range groupMap(members) {
+				m[k] = v
+			}
+			continue
+		}
+		if sub := groupMap(members); len(sub) > 0 {
+			m[a.Key] = sub
+		}
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"testing/slogtest"
+
+	"dirpx.dev/dlog/apis/record"
+)
+
+// capture is a pipeline.Pipeline that keeps the emitted records.
+type capture struct {
+	mu      sync.Mutex
+	records []record.Record
+}
+
+func (c *capture) Emit(_ context.Context, r record.Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, r)
+	return nil
+}
+
+func (c *capture) Flush(context.Context) error { return nil }
+
+// results converts the captured records into the maps slogtest expects.
+func (c *capture) results() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]map[string]any, 0, len(c.records))
+	for _, r := range c.records {
+		m := map[string]any{
+			slog.LevelKey:   r.Level,
+			slog.MessageKey: r.Message,
+		}
+		if !r.Time.IsZero() {
+			m[slog.TimeKey] = r.Time
+		}
+		for _, f := range r.Fields {
+			m[f.Key] = f.Value
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestHandler(t *testing.T) {
+	c := &capture{}
+	h := NewHandler(c, nil)
+	if err := slogtest.TestHandler(h, c.results); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandlerRun(t *testing.T) {
+	var c *capture
+	slogtest.Run(t, func(*testing.T) slog.Handler {
+		c = &capture{}
+		return NewHandler(c, nil)
+	}, func(t *testing.T) map[string]any {
+		rs := c.results()
+		if len(rs) != 1 {
+			t.Fatalf("got %d records, want 1", len(rs))
+		}
+		return rs[0]
+	})
+}
@@ -0,0 +1,83 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogbridge
+
+import (
+	"log/slog"
+
+	"dirpx.dev/dlog/apis/level"
+)
+
+const (
+	// LevelTrace is the slog level used for level.Trace.
+	// It sits one step (4) below slog.LevelDebug, following slog's spacing.
+	LevelTrace = slog.LevelDebug - 4
+
+	// LevelFatal is the slog level used for level.Fatal.
+	// It sits one step (4) above slog.LevelError, following slog's spacing.
+	LevelFatal = slog.LevelError + 4
+)
+
+// FromSlogLevel maps a slog level to a dlog level.
+//
+// slog levels are integers, and callers may define custom levels between
+// the well-known ones. Such levels are mapped to the closest dlog level
+// at or below them:
+//
+//	l <  Debug        -> trace
+//	Debug <= l < Info -> debug
+//	Info  <= l < Warn -> info
+//	Warn  <= l < Error-> warn
+//	Error <= l < Fatal-> error
+//	l >= LevelFatal   -> fatal
+func FromSlogLevel(l slog.Level) level.Level {
+	switch {
+	case l < slog.LevelDebug:
+		return level.Trace
+	case l < slog.LevelInfo:
+		return level.Debug
+	case l < slog.LevelWarn:
+		return level.Info
+	case l < slog.LevelError:
+		return level.Warn
+	case l < LevelFatal:
+		return level.Error
+	default:
+		return level.Fatal
+	}
+}
+
+// ToSlogLevel maps a dlog level to a slog level.
+// Unknown dlog levels are mapped to slog.LevelInfo.
+func ToSlogLevel(l level.Level) slog.Level {
+	switch l {
+	case level.Trace:
+		return LevelTrace
+	case level.Debug:
+		return slog.LevelDebug
+	case level.Info:
+		return slog.LevelInfo
+	case level.Warn:
+		return slog.LevelWarn
+	case level.Error:
+		return slog.LevelError
+	case level.Fatal:
+		return LevelFatal
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,224 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"dirpx.dev/dlog/apis"
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/field/fields"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// DefaultStackDepth is the number of frames captured when
+// LoggerOptions.StackDepth is zero.
+const DefaultStackDepth = 32
+
+// LoggerOptions configures a Logger.
+type LoggerOptions struct {
+	// Extractor builds the context Pack whose attributes are added to every
+	// slog record under the canonical field names (see Pack.Fields).
+	// If nil, context.FromContext is used.
+	Extractor dlogctx.Extractor
+
+	// Exit is called with status 1 after a Fatal record has been handled.
+	// If nil, os.Exit is used.
+	Exit func(code int)
+
+	// AddCaller records the call site of every logging call as the PC of
+	// the slog record (see HandlerOptions.AddSource).
+	AddCaller bool
+
+	// CallerSkip is the number of additional frames to skip when
+	// capturing the caller and the stack, for code that wraps Logger.
+	CallerSkip int
+
+	// StackLevel, if set, captures the goroutine stack for records at or
+	// above it. The stack is passed as a fields.Stack attribute holding
+	// []record.Frame, which Handler moves into record.Stack.
+	StackLevel *level.Level
+
+	// StackDepth bounds the number of captured frames; if zero,
+	// DefaultStackDepth is used.
+	StackDepth int
+}
+
+// Logger implements the dlog logger contracts on top of an arbitrary
+// slog.Handler. It lets code use the dlog API while keeping existing slog
+// handlers (e.g. a vendor-provided one) as the output.
+//
+// Logger is immutable and safe for concurrent use as long as the
+// underlying handler is.
+type Logger struct {
+	handler slog.Handler
+	opts    LoggerOptions
+
+	// fields are pre-bound via WithFields.
+	fields []field.Field
+	// ctx is the base context bound via WithContext; may be nil.
+	ctx context.Context
+}
+
+var (
+	_ apis.Logger        = (*Logger)(nil)
+	_ apis.FieldLogger   = (*Logger)(nil)
+	_ apis.ContextLogger = (*Logger)(nil)
+)
+
+// NewLogger returns a Logger that writes to h.
+// A nil opts is equivalent to the zero LoggerOptions.
+func NewLogger(h slog.Handler, opts *LoggerOptions) *Logger {
+	l := &Logger{handler: h}
+	if opts != nil {
+		l.opts = *opts
+	}
+	return l
+}
+
+// Enabled reports whether the handler accepts records at lvl.
+func (l *Logger) Enabled(lvl level.Level) bool {
+	return l.handler.Enabled(l.baseContext(), ToSlogLevel(lvl))
+}
+
+// Debug logs a debug-level message.
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...field.Field) {
+	l.log(ctx, level.Debug, msg, fields)
+}
+
+// Info logs an info-level message.
+func (l *Logger) Info(ctx context.Context, msg string, fields ...field.Field) {
+	l.log(ctx, level.Info, msg, fields)
+}
+
+// Warn logs a warning-level message.
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...field.Field) {
+	l.log(ctx, level.Warn, msg, fields)
+}
+
+// Error logs an error-level message.
+func (l *Logger) Error(ctx context.Context, msg string, fields ...field.Field) {
+	l.log(ctx, level.Error, msg, fields)
+}
+
+// Fatal logs a fatal message and then calls LoggerOptions.Exit(1).
+func (l *Logger) Fatal(ctx context.Context, msg string, fields ...field.Field) {
+	l.log(ctx, level.Fatal, msg, fields)
+	exit := l.opts.Exit
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(1)
+}
+
+// Log builds a slog.Record and passes it to the handler.
+//
+// Attributes are added in a stable order: context Pack attributes first,
+// then fields bound to ctx via context.WithFields, then fields bound via
+// WithFields, then the call-site fields.
+// Handler errors are ignored, as the Logger contract has no way to
+// report them.
+func (l *Logger) Log(ctx context.Context, lvl level.Level, msg string, fields ...field.Field) {
+	l.log(ctx, lvl, msg, fields)
+}
+
+// log implements Log. It must be called directly by the exported logging
+// methods so that the caller is always at the same depth.
+func (l *Logger) log(ctx context.Context, lvl level.Level, msg string, fs []field.Field) {
+	if ctx == nil {
+		ctx = l.baseContext()
+	}
+	sl := ToSlogLevel(lvl)
+	if !l.handler.Enabled(ctx, sl) {
+		return
+	}
+
+	pack := l.extract(ctx)
+	// Skip log and the exported method that called it.
+	const skip = 2
+	var pc uintptr
+	if l.opts.AddCaller {
+		pc = record.CallerPC(skip + l.opts.CallerSkip)
+	}
+	r := slog.NewRecord(time.Now(), sl, msg, pc)
+	for _, f := range pack.Fields() {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	for _, f := range dlogctx.FieldsFrom(ctx) {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	for _, f := range l.fields {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	for _, f := range fs {
+		r.AddAttrs(slog.Any(f.Key, f.Value))
+	}
+	if sl := l.opts.StackLevel; sl != nil && lvl >= *sl {
+		depth := l.opts.StackDepth
+		if depth <= 0 {
+			depth = DefaultStackDepth
+		}
+		r.AddAttrs(slog.Any(fields.Stack, record.Stack(skip+l.opts.CallerSkip, depth)))
+	}
+	_ = l.handler.Handle(ctx, r)
+}
+
+// WithFields returns a derived logger that always logs the given fields.
+func (l *Logger) WithFields(fields ...field.Field) apis.Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	out := *l
+	out.fields = append(append([]field.Field(nil), l.fields...), fields...)
+	return &out
+}
+
+// WithContext returns a derived logger that extracts context attributes
+// from ctx in addition to the context passed at each call site.
+// Call-site values override the bound ones.
+func (l *Logger) WithContext(ctx context.Context) apis.Logger {
+	out := *l
+	out.ctx = ctx
+	return &out
+}
+
+// baseContext returns the bound context or context.Background.
+func (l *Logger) baseContext() context.Context {
+	if l.ctx != nil {
+		return l.ctx
+	}
+	return context.Background()
+}
+
+// extract builds the Pack for a call, overlaying the call-site context
+// on top of the bound one.
+func (l *Logger) extract(ctx context.Context) dlogctx.Pack {
+	ex := l.opts.Extractor
+	if ex == nil {
+		ex = dlogctx.FromContext()
+	}
+	p := ex.Extract(ctx)
+	if l.ctx != nil && l.ctx != ctx {
+		p = dlogctx.Merge(ex.Extract(l.ctx), p)
+	}
+	return p
+}
@@ -0,0 +1,33 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package stdlog redirects unstructured text output into a dlog pipeline.
+//
+// Third-party code frequently logs via the standard library log package
+// or writes straight to os.Stderr. Writer is an io.Writer that splits the
+// written bytes into lines and turns each logical entry into a
+// record.Record:
+//
+//  1. The level is inferred from a configurable set of prefixes such as
+//     "ERROR:" or "[warn]"; the matched prefix is removed from the message.
+//  2. Lines that already contain JSON objects or logfmt pairs are parsed
+//     into fields; well-known keys (msg, level, time) populate the record.
+//     A JSON object may follow some text, which is kept as the message.
+//  3. Continuation lines (stack traces, indented output) are grouped with
+//     the preceding line into a single multi-line record.
+//
+// RedirectStdLog points the standard library logger at a Writer.
+package stdlog
@@ -0,0 +1,95 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stdlog
+
+import (
+	"sort"
+	"strings"
+
+	"dirpx.dev/dlog/apis/level"
+)
+
+// Prefix maps a leading marker of a line to a level.
+// Matching is case-insensitive and ignores leading whitespace.
+type Prefix struct {
+	// Text is the marker, e.g. "ERROR:" or "[warn]".
+	Text string
+
+	// Level is assigned to lines that start with Text.
+	Level level.Level
+}
+
+// DefaultPrefixes returns the built-in prefix set: for every level name
+// and common alias (warning, err, panic) both the "NAME:" and "[name]"
+// forms are recognized.
+func DefaultPrefixes() []Prefix {
+	names := []struct {
+		name string
+		lvl  level.Level
+	}{
+		{"trace", level.Trace},
+		{"debug", level.Debug},
+		{"info", level.Info},
+		{"warn", level.Warn},
+		{"warning", level.Warn},
+		{"error", level.Error},
+		{"err", level.Error},
+		{"fatal", level.Fatal},
+		{"panic", level.Fatal},
+	}
+	out := make([]Prefix, 0, 2*len(names))
+	for _, n := range names {
+		out = append(out,
+			Prefix{Text: n.name + ":", Level: n.lvl},
+			Prefix{Text: "[" + n.name + "]", Level: n.lvl},
+		)
+	}
+	return out
+}
+
+// prefixMatcher matches lines against a set of prefixes, preferring the
+// longest one so that "warning:" wins over "warn".
+type prefixMatcher []Prefix
+
+// newPrefixMatcher normalizes and sorts ps. Empty markers are skipped.
+func newPrefixMatcher(ps []Prefix) prefixMatcher {
+	m := make(prefixMatcher, 0, len(ps))
+	for _, p := range ps {
+		if p.Text == "" {
+			continue
+		}
+		m = append(m, Prefix{Text: strings.ToLower(p.Text), Level: p.Level})
+	}
+	sort.SliceStable(m, func(i, j int) bool {
+		return len(m[i].Text) > len(m[j].Text)
+	})
+	return m
+}
+
+// match returns the level for line and the line with the marker removed.
+func (m prefixMatcher) match(line string) (level.Level, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, p := range m {
+		if len(trimmed) < len(p.Text) {
+			continue
+		}
+		if strings.EqualFold(trimmed[:len(p.Text)], p.Text) {
+			return p.Level, strings.TrimLeft(trimmed[len(p.Text):], " \t"), true
+		}
+	}
+	return 0, line, false
+}
@@ -0,0 +1,229 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stdlog
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+)
+
+// Well-known keys that are projected onto the record instead of being
+// kept as fields when a structured line is parsed.
+var (
+	messageKeys = []string{"msg", "message"}
+	levelKeys   = []string{"level", "lvl", "severity"}
+	timeKeys    = []string{"time", "ts", "timestamp"}
+)
+
+// structured is the result of parsing a JSON or logfmt line.
+type structured struct {
+	msg      string
+	lvl      level.Level
+	hasLevel bool
+	t        time.Time
+	fields   []field.Field
+}
+
+// parseJSON parses line as a JSON object, or as text followed by a JSON
+// object such as `request done {"status":200}`. The object must start at
+// the beginning of the line or after a blank and end the line. Text in
+// front of the object is kept as the message, followed by the object's
+// message key if it has one.
+func parseJSON(line string) (structured, bool) {
+	s := strings.TrimSpace(line)
+	if len(s) < 2 || s[len(s)-1] != '}' {
+		return structured{}, false
+	}
+	for i := 0; i < len(s); i++ {
+		j := strings.IndexByte(s[i:], '{')
+		if j < 0 {
+			break
+		}
+		i += j
+		if i > 0 && s[i-1] != ' ' && s[i-1] != '\t' {
+			continue
+		}
+		m, ok := decodeObject(s[i:])
+		if !ok {
+			continue
+		}
+		out := project(m)
+		if text := strings.TrimSpace(s[:i]); text != "" {
+			if out.msg != "" {
+				text += " " + out.msg
+			}
+			out.msg = text
+		}
+		return out, true
+	}
+	return structured{}, false
+}
+
+// decodeObject decodes s if it is exactly one JSON object.
+func decodeObject(s string) (map[string]any, bool) {
+	var m map[string]any
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	if err := dec.Decode(&m); err != nil || m == nil || dec.InputOffset() != int64(len(s)) {
+		return nil, false
+	}
+	return m, true
+}
+
+// parseLogfmt parses line as logfmt (key=value pairs separated by spaces,
+// values optionally double-quoted). Every token must be a pair; otherwise
+// the line is treated as plain text.
+func parseLogfmt(line string) (structured, bool) {
+	s := strings.TrimSpace(line)
+	if s == "" || !strings.Contains(s, "=") {
+		return structured{}, false
+	}
+	m := make(map[string]any)
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq <= 0 {
+			return structured{}, false
+		}
+		key := s[:eq]
+		if strings.ContainsAny(key, " \t\"") {
+			return structured{}, false
+		}
+		s = s[eq+1:]
+
+		var val string
+		if strings.HasPrefix(s, `"`) {
+			end := closingQuote(s)
+			if end < 0 {
+				return structured{}, false
+			}
+			unq, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return structured{}, false
+			}
+			val, s = unq, s[end+1:]
+			if len(s) > 0 && s[0] != ' ' && s[0] != '\t' {
+				return structured{}, false
+			}
+		} else {
+			end := strings.IndexAny(s, " \t")
+			if end < 0 {
+				end = len(s)
+			}
+			val, s = s[:end], s[end:]
+		}
+		m[key] = val
+		s = strings.TrimLeft(s, " \t")
+	}
+	return project(m), true
+}
+
+// closingQuote returns the index of the quote that terminates the quoted
+// string at the start of s, honoring backslash escapes.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// project moves well-known keys out of m and converts the remaining
+// entries into fields sorted by key for deterministic output.
+func project(m map[string]any) structured {
+	var out structured
+	if k, v, ok := take(m, messageKeys); ok {
+		if s, ok := v.(string); ok {
+			out.msg = s
+		} else {
+			m[k] = v
+		}
+	}
+	if k, v, ok := take(m, levelKeys); ok {
+		s, _ := v.(string)
+		if lvl, err := level.ParseLevel(s); err == nil {
+			out.lvl, out.hasLevel = lvl, true
+		} else {
+			m[k] = v
+		}
+	}
+	if k, v, ok := take(m, timeKeys); ok {
+		s, _ := v.(string)
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			out.t = t
+		} else {
+			m[k] = v
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out.fields = make([]field.Field, 0, len(keys))
+	for _, k := range keys {
+		out.fields = append(out.fields, field.New(k, numbers(m[k])))
+	}
+	return out
+}
+
+// numbers replaces the json.Number values in v, which JSON parsing
+// produces to keep integers exact, with int64 or float64 values, so that
+// encoders and expressions treat them as numbers rather than text. Maps
+// and slices are modified in place.
+func numbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case map[string]any:
+		for k, e := range x {
+			x[k] = numbers(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = numbers(e)
+		}
+	}
+	return v
+}
+
+// take removes the first present key from m and returns it with its value.
+func take(m map[string]any, keys []string) (string, any, bool) {
+	for _, k := range keys {
+		if v, ok := m[k]; ok {
+			delete(m, k)
+			return k, v, true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stdlog
+
+import (
+	"reflect"
+	"testing"
+
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		line   string
+		ok     bool
+		msg    string
+		lvl    level.Level
+		fields []field.Field
+	}{
+		{line: `plain text`},
+		{line: `{"msg":"done","level":"warn","status":500,"ratio":0.5}`, ok: true, msg: "done", lvl: level.Warn,
+			fields: []field.Field{field.New("ratio", 0.5), field.New("status", int64(500))}},
+		{line: `request done {"status":200,"msg":"ok"}`, ok: true, msg: "request done ok",
+			fields: []field.Field{field.New("status", int64(200))}},
+		{line: `{"big":12345678901234567890,"nested":{"n":1,"l":[2,"x"]}}`, ok: true,
+			fields: []field.Field{
+				field.New("big", 12345678901234567890.0),
+				field.New("nested", map[string]any{"n": int64(1), "l": []any{int64(2), "x"}}),
+			}},
+		{line: `prefix{"a":1}`},
+		{line: `{"a":1} trailing`},
+	}
+	for _, tt := range tests {
+		s, ok := parseJSON(tt.line)
+		if ok != tt.ok {
+			t.Errorf("parseJSON(%q) ok = %v, want %v", tt.line, ok, tt.ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if s.msg != tt.msg || s.lvl != tt.lvl {
+			t.Errorf("parseJSON(%q) = msg %q level %v, want %q %v", tt.line, s.msg, s.lvl, tt.msg, tt.lvl)
+		}
+		if !reflect.DeepEqual(s.fields, tt.fields) {
+			t.Errorf("parseJSON(%q) fields = %#v, want %#v", tt.line, s.fields, tt.fields)
+		}
+	}
+}
@@ -0,0 +1,375 @@
+/*
+   Copyright 2025 The DIRPX Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package stdlog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	dlogctx "dirpx.dev/dlog/apis/context"
+	"dirpx.dev/dlog/apis/field"
+	"dirpx.dev/dlog/apis/level"
+	"dirpx.dev/dlog/apis/pipeline"
+	"dirpx.dev/dlog/apis/record"
+)
+
+// DefaultGroupTimeout is how long a Writer waits for continuation lines
+// before emitting a pending multi-line record.
+const DefaultGroupTimeout = 100 * time.Millisecond
+
+// Options configures a Writer.
+type Options struct {
+	// Level is assigned to lines without a recognized prefix or level key.
+	// If nil, level.Info is used.
+	Level *level.Level
+
+	// Prefixes are the level markers recognized at the start of a line.
+	// If nil, DefaultPrefixes is used; an empty non-nil slice disables
+	// prefix detection.
+	Prefixes []Prefix
+
+	// DisableJSON turns off parsing of lines that contain a JSON object.
+	DisableJSON bool
+
+	// DisableLogfmt turns off parsing of logfmt lines.
+	DisableLogfmt bool
+
+	// Continuation reports whether line continues the entry whose last
+	// line was prev. If nil, DefaultContinuation is used.
+	Continuation func(prev, line string) bool
+
+	// GroupTimeout bounds how long a pending entry waits for continuation
+	// lines, and how long a trailing line without a newline waits for the
+	// rest of it before it is taken as complete. Zero means
+	// DefaultGroupTimeout; a negative value disables the timer, so entries
+	// are emitted on the next non-continuation line or Flush.
+	GroupTimeout time.Duration
+
+	// Extractor builds the context Pack attached to every record. It is
+	// called with context.Background since writers carry no request context.
+	// If nil, records carry an empty Pack.
+	Extractor dlogctx.Extractor
+
+	// Fields are static fields appended to every record
+	// (e.g. a "source" field naming the redirected library).
+	Fields []field.Field
+}
+
+// Writer is an io.Writer that turns written text into dlog records.
+// It is safe for concurrent use.
+type Writer struct {
+	pipeline pipeline.Pipeline
+	opts     Options
+	level    level.Level
+	prefixes prefixMatcher
+	cont     func(prev, line string) bool
+
+	mu sync.Mutex
+	// emitMu is held while records taken from the grouping state are
+	// emitted; it is acquired before mu is released (see release), so
+	// records reach the pipeline in the order they were written.
+	emitMu sync.Mutex
+	// partial holds bytes written after the last newline.
+	partial []byte
+	// pending is the entry still collecting continuation lines.
+	pending *entry
+	timer   *time.Timer
+}
+
+// entry is a logical log entry that may span multiple lines.
+type entry struct {
+	rec   record.Record
+	lines []string
+}
+
+var _ io.Writer = (*Writer)(nil)
+
+// NewWriter returns a Writer that emits into p.
+// A nil opts is equivalent to the zero Options.
+func NewWriter(p pipeline.Pipeline, opts *Options) *Writer {
+	w := &Writer{pipeline: p}
+	if opts != nil {
+		w.opts = *opts
+	}
+	w.level = level.Info
+	if w.opts.Level != nil {
+		w.level = *w.opts.Level
+	}
+	if w.opts.Prefixes == nil {
+		w.prefixes = newPrefixMatcher(DefaultPrefixes())
+	} else {
+		w.prefixes = newPrefixMatcher(w.opts.Prefixes)
+	}
+	w.cont = w.opts.Continuation
+	if w.cont == nil {
+		w.cont = DefaultContinuation
+	}
+	if w.opts.GroupTimeout == 0 {
+		w.opts.GroupTimeout = DefaultGroupTimeout
+	}
+	return w
+}
+
+// Write splits b into lines and emits every completed entry.
+// It always consumes all of b; the returned error is the first error
+// reported by the pipeline, if any.
+func (w *Writer) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	w.partial = append(w.partial, b...)
+
+	var ready []record.Record
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSuffix(string(w.partial[:i]), "\r")
+		w.partial = w.partial[i+1:]
+		if r, ok := w.addLine(line); ok {
+			ready = append(ready, r)
+		}
+	}
+	if len(w.partial) == 0 {
+		w.partial = nil
+	}
+	w.armTimer()
+	return len(b), w.release(ready)
+}
+
+// Flush emits any buffered partial line and pending multi-line entry.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	var ready []record.Record
+	if len(w.partial) > 0 {
+		if r, ok := w.addLine(string(w.partial)); ok {
+			ready = append(ready, r)
+		}
+		w.partial = nil
+	}
+	if r, ok := w.takePending(); ok {
+		ready = append(ready, r)
+	}
+	return w.release(ready)
+}
+
+// Close flushes the writer. It does not close the underlying pipeline.
+func (w *Writer) Close() error {
+	return w.Flush()
+}
+
+// addLine feeds one line into the grouping state machine. It returns the
+// previous entry when line starts a new one. Must be called with mu held.
+func (w *Writer) addLine(line string) (record.Record, bool) {
+	if w.pending != nil {
+		prev := w.pending.lines[len(w.pending.lines)-1]
+		if w.cont(prev, line) {
+			w.pending.lines = append(w.pending.lines, line)
+			return record.Record{}, false
+		}
+	}
+	if line == "" {
+		return record.Record{}, false
+	}
+	done, ok := w.takePending()
+	w.pending = &entry{rec: w.parse(line), lines: []string{line}}
+	return done, ok
+}
+
+// takePending finalizes and clears the pending entry. Must be called with mu held.
+func (w *Writer) takePending() (record.Record, bool) {
+	if w.pending == nil {
+		return record.Record{}, false
+	}
+	e := w.pending
+	w.pending = nil
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+
+	// Continuation lines are appended to the message verbatim;
+	// trailing blank lines carry no information and are trimmed.
+	rest := e.lines[1:]
+	for len(rest) > 0 && strings.TrimSpace(rest[len(rest)-1]) == "" {
+		rest = rest[:len(rest)-1]
+	}
+	if len(rest) > 0 {
+		e.rec.Message += "\n" + strings.Join(rest, "\n")
+	}
+	return e.rec, true
+}
+
+// armTimer schedules emission of the pending entry and the partial line
+// after GroupTimeout. Must be called with mu held.
+func (w *Writer) armTimer() {
+	if (w.pending == nil && len(w.partial) == 0) || w.opts.GroupTimeout < 0 {
+		return
+	}
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.opts.GroupTimeout, w.expire)
+		return
+	}
+	w.timer.Reset(w.opts.GroupTimeout)
+}
+
+// expire emits the partial line and the pending entry once the group
+// timeout has elapsed.
+func (w *Writer) expire() {
+	_ = w.Flush()
+}
+
+// release unlocks mu and emits recs. It takes emitMu before unlocking, so
+// a later release waits until earlier records are emitted. Must be called
+// with mu held.
+func (w *Writer) release(recs []record.Record) error {
+	if len(recs) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	w.emitMu.Lock()
+	w.mu.Unlock()
+	defer w.emitMu.Unlock()
+	return w.emit(recs...)
+}
+
+// parse builds a record from the first line of an entry.
+func (w *Writer) parse(line string) record.Record {
+	lvl, msg, ok := w.prefixes.match(line)
+	if !ok {
+		lvl = w.level
+	}
+
+	var fs []field.Field
+	t := time.Now()
+	if s, ok := w.parseStructured(msg); ok {
+		msg = s.msg
+		if s.hasLevel {
+			lvl = s.lvl
+		}
+		if !s.t.IsZero() {
+			t = s.t
+		}
+		fs = s.fields
+	}
+	if len(w.opts.Fields) > 0 {
+		fs = append(fs, w.opts.Fields...)
+	}
+
+	var pack dlogctx.Pack
+	if w.opts.Extractor != nil {
+		pack = w.opts.Extractor.Extract(context.Background())
+	}
+	return record.NewRecord(t, lvl, msg, pack, fs, nil)
+}
+
+// parseStructured tries the enabled structured formats in turn.
+func (w *Writer) parseStructured(msg string) (structured, bool) {
+	if !w.opts.DisableJSON {
+		if s, ok := parseJSON(msg); ok {
+			return s, true
+		}
+	}
+	if !w.opts.DisableLogfmt {
+		if s, ok := parseLogfmt(msg); ok {
+			return s, true
+		}
+	}
+	return structured{}, false
+}
+
+// emit pushes records into the pipeline and returns the first error.
+func (w *Writer) emit(recs ...record.Record) error {
+	var first error
+	for _, r := range recs {
+		if err := w.pipeline.Emit(context.Background(), r); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// DefaultContinuation is the default multi-line grouping rule. A line
+// continues the current entry when it:
+//   - is empty or starts with whitespace (indented stack frames, Java "\tat");
+//   - starts with a stack-trace marker ("goroutine ", "created by ",
+//     "Caused by:", "[signal ");
+//   - is a Go stack frame function line ("pkg.fn(...)") following a
+//     goroutine header or an indented file line;
+//   - is the closing exception line of a Python traceback
+//     ("ValueError: bad input") following an indented line.
+//
+// A Python "Traceback " header starts an entry of its own: uncaught
+// exceptions are printed independently of whatever was logged before.
+func DefaultContinuation(prev, line string) bool {
+	if line == "" || line[0] == ' ' || line[0] == '\t' {
+		return true
+	}
+	for _, p := range []string{"goroutine ", "created by ", "Caused by:", "[signal "} {
+		if strings.HasPrefix(line, p) {
+			return true
+		}
+	}
+	indented := prev != "" && (prev[0] == ' ' || prev[0] == '\t')
+	if strings.HasSuffix(line, ")") && strings.Contains(line, "(") {
+		return strings.HasPrefix(prev, "goroutine ") || strings.HasPrefix(prev, "\t")
+	}
+	return indented && isPythonException(line)
+}
+
+// pythonExceptionSuffixes end the names of Python exception classes.
+var pythonExceptionSuffixes = []string{"Error", "Exception", "Exit", "Interrupt", "Iteration", "Warning"}
+
+// isPythonException reports whether line is the last line of a Python
+// traceback: a dotted exception class name, optionally followed by ": "
+// and the exception message.
+func isPythonException(line string) bool {
+	name, _, _ := strings.Cut(line, ":")
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return false
+	}
+	for _, r := range name {
+		if r != '.' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	for _, suf := range pythonExceptionSuffixes {
+		if strings.HasSuffix(name, suf) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedirectStdLog points the standard library logger at w and clears its
+// flags, since records carry their own timestamp. The returned function
+// restores the previous output, flags and prefix.
+func RedirectStdLog(w *Writer) (restore func()) {
+	out, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}